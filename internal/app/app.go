@@ -3,10 +3,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"log"
 	"mortgage-calculator/internal/cache"
 	"mortgage-calculator/internal/calculator"
+	"mortgage-calculator/internal/calendar"
 	"mortgage-calculator/internal/config"
 	"mortgage-calculator/internal/controller"
+	"mortgage-calculator/internal/keyrate"
 	"mortgage-calculator/internal/middleware"
 	"net/http"
 	"time"
@@ -20,7 +23,32 @@ type App struct {
 
 func NewApp(cfg *config.Config) (*App, error) {
 	// Initialize dependencies
-	calc := calculator.NewCalculator()
+	opts := []calculator.Option{
+		calculator.WithPrograms(cfg.Programs),
+		calculator.WithRounding(cfg.Rounding),
+		calculator.WithDebtLoadLimits(cfg.DebtLoad),
+	}
+	if cfg.KeyRateFile != "" {
+		history, err := keyrate.Load(cfg.KeyRateFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, calculator.WithKeyRates(history))
+	}
+	if cfg.HolidayFile != "" {
+		cal, err := calendar.Load(cfg.HolidayFile)
+		if err != nil {
+			return nil, err
+		}
+		from, to := cal.Range()
+		log.Printf("Holiday calendar covers %s to %s", from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if next := time.Now().AddDate(1, 0, 0); !cal.Covers(next) {
+			log.Printf("Warning: %s does not cover %d, payment dates will skip weekends only", cfg.HolidayFile, next.Year())
+		}
+		opts = append(opts, calculator.WithCalendar(cal))
+	}
+
+	calc := calculator.NewCalculator(opts...)
 	cache := cache.NewInMemoryCache()
 	controller := controller.NewMortgageController(calc, cache)
 
@@ -0,0 +1,115 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+func (c *calculatorImpl) Affordability(req *model.AffordabilityRequest) (*model.AffordabilityResult, error) {
+	program, err := c.program(req.Program)
+	if err != nil {
+		return nil, err
+	}
+	if program.MaxMonths > 0 && req.Months > program.MaxMonths {
+		return nil, ErrTermTooLong
+	}
+
+	maxPayment := money.FromFloat(req.MaxMonthlyPayment)
+	downPayment := money.FromFloat(req.DownPayment)
+	limitedBy := model.LimitedByPayment
+
+	// The income caps the payment at the maximum debt load
+	if req.MonthlyIncome > 0 {
+		byIncome := c.paymentByIncome(money.FromFloat(req.MonthlyIncome), money.FromFloat(req.DebtPayments))
+		if maxPayment == 0 || byIncome < maxPayment {
+			maxPayment, limitedBy = byIncome, model.LimitedByDebtLoad
+		}
+	}
+
+	// With a rate grid the rate depends on the loan-to-value of the loan
+	// itself, every band is tried and the largest loan staying in its band wins
+	rate := program.Rate
+	var tier *model.RateTier
+	var tierLoan money.Money
+	if program.RateGrid != nil {
+		loanAt := func(rate float64) money.Money {
+			loan, _ := c.affordableLoan(program, rate, maxPayment, downPayment, req.Months, limitedBy)
+			return loan
+		}
+		objectCost := func(loan money.Money) money.Money {
+			return loan + downPayment
+		}
+		if tierLoan, tier, err = c.bestTier(program.RateGrid, program.Rate, req.Months, loanAt, objectCost); err != nil {
+			return nil, err
+		}
+		if tier == nil {
+			return nil, ErrNotAffordable
+		}
+		rate = adjustRate(rate, tier.Adjustment)
+	}
+
+	maxLoan, limitedBy := c.affordableLoan(program, rate, maxPayment, downPayment, req.Months, limitedBy)
+	if tier != nil && tierLoan < maxLoan {
+		maxLoan, limitedBy = tierLoan, model.LimitedByRateTier
+	}
+
+	if maxLoan <= 0 {
+		return nil, ErrNotAffordable
+	}
+
+	result := &model.AffordabilityResult{
+		Program:        selectedProgram(req.Program, program.ID),
+		Rate:           rate,
+		RateTier:       tier,
+		Months:         req.Months,
+		DownPayment:    downPayment.Float(),
+		MaxLoan:        maxLoan.Float(),
+		MaxObjectCost:  (maxLoan + downPayment).Float(),
+		MonthlyPayment: c.programPayment(program, rate, maxLoan, req.Months).Float(),
+		LimitedBy:      limitedBy,
+	}
+	if req.MonthlyIncome > 0 {
+		payment := money.FromFloat(result.MonthlyPayment)
+		result.DebtLoad = c.debtLoad(money.FromFloat(req.MonthlyIncome), money.FromFloat(req.DebtPayments), payment)
+	}
+
+	return result, nil
+}
+
+// affordableLoan returns the largest loan at the rate within the payment,
+// the program down payment share and the program loan limit, and the
+// constraint that capped it. limitedBy names the payment constraint.
+func (c *calculatorImpl) affordableLoan(program model.Program, rate float64, maxPayment, downPayment money.Money, months int, limitedBy string) (money.Money, string) {
+	// The loan whose annuity fits into the budget
+	maxLoan := c.maxProgramLoan(program, rate, maxPayment, months)
+
+	// The down payment must stay above the program share of the object cost:
+	// down >= share * (loan + down), so loan <= down * (1 - share) / share
+	if share := program.MinDownPayment; share > 0 {
+		byDownPayment := downPayment.Percent((1-share)/share, 100, 1, money.HalfUp).Round(money.Ruble, money.HalfUp)
+		for byDownPayment > 0 && !c.downPaymentCovers(byDownPayment+downPayment, downPayment, share) {
+			byDownPayment -= money.Ruble
+		}
+		if byDownPayment < maxLoan {
+			maxLoan, limitedBy = byDownPayment, model.LimitedByDownPayment
+		}
+	}
+
+	if program.MaxLoan > 0 {
+		if limit := money.FromFloat(program.MaxLoan); limit < maxLoan {
+			maxLoan, limitedBy = limit, model.LimitedByMaxLoan
+		}
+	}
+
+	return maxLoan, limitedBy
+}
+
+// maxLoanByPayment returns the largest loan in whole rubles whose rounded
+// annuity payment does not exceed payment.
+func maxLoanByPayment(payment money.Money, annualRate float64, months int, rounding money.Rounding) money.Money {
+	loan := presentValue(payment, annualRate, months).Round(money.Ruble, money.HalfUp)
+	for loan > 0 && annuityPayment(loan, annualRate, months, rounding) > payment {
+		loan -= money.Ruble
+	}
+	return loan
+}
@@ -0,0 +1,86 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"testing"
+)
+
+func TestCalculator_Affordability(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: model.ProgramSalary, Rate: 8, MinDownPayment: 0.2},
+		{ID: "capped", Rate: 8, MinDownPayment: 0.2, MaxLoan: 3_000_000},
+	}))
+
+	tests := []struct {
+		name          string
+		request       *model.AffordabilityRequest
+		wantLoan      float64
+		wantLimitedBy string
+		wantError     error
+	}{
+		{
+			name:          "limited by monthly payment",
+			request:       &model.AffordabilityRequest{MaxMonthlyPayment: 33458, DownPayment: 2_000_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantLoan:      4_000_047,
+			wantLimitedBy: model.LimitedByPayment,
+		},
+		{
+			name:          "limited by down payment share",
+			request:       &model.AffordabilityRequest{MaxMonthlyPayment: 100_000, DownPayment: 1_000_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantLoan:      4_000_000,
+			wantLimitedBy: model.LimitedByDownPayment,
+		},
+		{
+			name:          "limited by program maximum",
+			request:       &model.AffordabilityRequest{MaxMonthlyPayment: 100_000, DownPayment: 2_000_000, Months: 240, Program: model.MortgageProgram{ID: "capped"}},
+			wantLoan:      3_000_000,
+			wantLimitedBy: model.LimitedByMaxLoan,
+		},
+		{
+			name:      "no down payment",
+			request:   &model.AffordabilityRequest{MaxMonthlyPayment: 100_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantError: ErrNotAffordable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Affordability(tt.request)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.MaxLoan != tt.wantLoan {
+				t.Errorf("Expected max loan %f, got %f", tt.wantLoan, result.MaxLoan)
+			}
+			if result.LimitedBy != tt.wantLimitedBy {
+				t.Errorf("Expected limit %s, got %s", tt.wantLimitedBy, result.LimitedBy)
+			}
+			if result.MaxObjectCost != result.MaxLoan+tt.request.DownPayment {
+				t.Errorf("Expected object cost %f, got %f", result.MaxLoan+tt.request.DownPayment, result.MaxObjectCost)
+			}
+			if result.MonthlyPayment > tt.request.MaxMonthlyPayment {
+				t.Errorf("Payment %f exceeds budget %f", result.MonthlyPayment, tt.request.MaxMonthlyPayment)
+			}
+
+			// The affordable object passes the regular calculation
+			_, err = calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     result.MaxObjectCost,
+				InitialPayment: result.DownPayment,
+				Months:         result.Months,
+				Program:        tt.request.Program,
+			})
+			if err != nil {
+				t.Errorf("Calculate rejected the affordable object: %v", err)
+			}
+		})
+	}
+}
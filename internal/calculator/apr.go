@@ -0,0 +1,109 @@
+package calculator
+
+import (
+	"math"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// creditCost is the full cost of credit computed from the borrower cash flows.
+type creditCost struct {
+	// effectiveRate is the compounded annual IRR, %.
+	effectiveRate float64
+	// fullCostRate is the monthly IRR times 12 as the ПСК is disclosed, %.
+	fullCostRate float64
+	fees         money.Money
+	insurance    money.Money
+}
+
+// fullCreditCost returns the effective rates of the loan repaid by schedule
+// with the given fees and yearly insurance premiums.
+func fullCreditCost(loanSum money.Money, schedule []loanPeriod, fees *model.CreditFees, premiums []money.Money) creditCost {
+	flows := make([]money.Money, len(schedule)+1)
+	flows[0] = loanSum
+	for _, p := range schedule {
+		flows[p.number] = p.disbursement - (p.payment + p.prepayment)
+		flows[0] -= p.disbursement
+	}
+
+	var total money.Money
+	charge := func(period int, amount money.Money) {
+		flows[period] -= amount
+		total += amount
+	}
+
+	if fees != nil {
+		for _, fee := range fees.OneTime {
+			charge(0, money.FromFloat(fee.Amount))
+		}
+
+		for _, fee := range fees.Recurring {
+			amount := money.FromFloat(fee.Amount)
+			for _, p := range schedule {
+				switch {
+				case fee.Period == model.FeeMonthly:
+					charge(p.number, amount)
+				case p.number == 1:
+					// The first year is paid at issue
+					charge(0, amount)
+				case (p.number-1)%12 == 0:
+					// Next years are paid with the previous payment
+					charge(p.number-1, amount)
+				}
+			}
+		}
+	}
+
+	// Insurance for year k is paid with payment 12k, the first year at issue
+	var insurance money.Money
+	for year, premium := range premiums {
+		flows[year*12] -= premium
+		insurance += premium
+	}
+
+	monthly := irr(flows)
+	return creditCost{
+		effectiveRate: round3(math.Expm1(12*math.Log1p(monthly)) * 100),
+		fullCostRate:  round3(monthly * 12 * 100),
+		fees:          total,
+		insurance:     insurance,
+	}
+}
+
+// irr returns the monthly rate that makes the net present value of flows
+// zero. Loan flows start with the inflow followed by outflows, so NPV
+// increases with the rate and bisection converges. Flows that never change
+// sign have no rate, zero is returned for them.
+func irr(flows []money.Money) float64 {
+	var inflow, outflow bool
+	for _, flow := range flows {
+		inflow = inflow || flow > 0
+		outflow = outflow || flow < 0
+	}
+	if !inflow || !outflow {
+		return 0
+	}
+
+	npv := func(rate float64) float64 {
+		var sum float64
+		for i, flow := range flows {
+			sum += flow.Float() / math.Pow(1+rate, float64(i))
+		}
+		return sum
+	}
+
+	low, high := -0.99, 1.0
+	for i := 0; i < 200 && high-low > 1e-12; i++ {
+		mid := (low + high) / 2
+		if npv(mid) < 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"math"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculateFullCreditCost(t *testing.T) {
+	tests := []struct {
+		name             string
+		fees             *model.CreditFees
+		wantFullCostRate float64
+		wantEffective    float64
+		wantFeesTotal    float64
+	}{
+		{
+			name:             "no fees",
+			wantFullCostRate: 8,
+			wantEffective:    8.3,
+		},
+		{
+			name: "one-time and recurring fees",
+			fees: &model.CreditFees{
+				OneTime: []model.Fee{
+					{Name: "appraisal", Amount: 5_000},
+					{Name: "commission", Amount: 40_000},
+				},
+				Recurring: []model.RecurringFee{
+					{Name: "insurance", Amount: 20_000, Period: model.FeeAnnual},
+					{Name: "account", Amount: 100, Period: model.FeeMonthly},
+				},
+			},
+			wantFullCostRate: 8.89,
+			wantEffective:    9.262,
+			wantFeesTotal:    5_000 + 40_000 + 20_000*20 + 100*240,
+		},
+	}
+
+	calc := NewCalculator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Fees:           tt.fees,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			aggregates := result.Aggregates
+			if math.Abs(aggregates.FullCostRate-tt.wantFullCostRate) > 0.05 {
+				t.Errorf("Expected full cost rate about %f, got %f", tt.wantFullCostRate, aggregates.FullCostRate)
+			}
+			if math.Abs(aggregates.EffectiveRate-tt.wantEffective) > 0.05 {
+				t.Errorf("Expected effective rate about %f, got %f", tt.wantEffective, aggregates.EffectiveRate)
+			}
+			if aggregates.FeesTotal != tt.wantFeesTotal {
+				t.Errorf("Expected fees total %f, got %f", tt.wantFeesTotal, aggregates.FeesTotal)
+			}
+			if aggregates.Rate != 8 {
+				t.Errorf("Expected nominal rate to stay 8, got %f", aggregates.Rate)
+			}
+		})
+	}
+}
+
+func TestIRR_FlowsWithoutSignChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []money.Money
+	}{
+		{"zero flows", []money.Money{0, 0, 0}},
+		{"outflows only", []money.Money{0, -100, -100}},
+		{"inflows only", []money.Money{100, 0, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := irr(tt.flows); got != 0 {
+				t.Errorf("Expected rate 0, got %v", got)
+			}
+		})
+	}
+}
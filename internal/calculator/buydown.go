@@ -0,0 +1,73 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"time"
+)
+
+// buyDownRates replaces the rate of the first months with the subsidized
+// one, zero months subsidize the whole term.
+func buyDownRates(rates rateFunc, rate float64, months int) rateFunc {
+	return func(period int, periodStart time.Time) float64 {
+		if months == 0 || period <= months {
+			return rate
+		}
+		return rates(period, periodStart)
+	}
+}
+
+// compareBuyDown prices the same purchase without the developer commission
+// at the regular rates, combined above the subsidy limit as the buy-down
+// loan is. The buy-down schedule is repaid from the inflated price, its
+// effective rate is measured against the discounted loan.
+func compareBuyDown(params scheduleParams, regularRates rateFunc, limit money.Money, excessRate float64, buyDown *model.BuyDown, objectCost, downPayment money.Money, schedule []loanPeriod) (*model.BuyDownComparison, error) {
+	discountedPrice := objectCost - objectCost.Percent(buyDown.Commission, 100, 1, params.rounding.Mode)
+	if discountedPrice <= downPayment {
+		return nil, ErrBuyDownCommissionTooLarge
+	}
+
+	regular := params
+	regular.loanSum = discountedPrice - downPayment
+	regular.rates = regularRates
+	var regularSchedule []loanPeriod
+	if limit > 0 && regular.loanSum > limit {
+		regularSchedule = combinedSchedule(splitLoan(regular, limit, excessRate))
+	} else {
+		regularSchedule = buildSchedule(regular)
+	}
+
+	buyDownCost, regularCost := downPayment, downPayment
+	flows := make([]money.Money, len(schedule)+1)
+	flows[0] = regular.loanSum
+	for _, p := range schedule {
+		buyDownCost += p.payment
+		flows[p.number] = -p.payment
+	}
+	for _, p := range regularSchedule {
+		regularCost += p.payment
+	}
+
+	var afterBuyDown money.Money
+	if buyDown.Months > 0 && buyDown.Months < len(schedule)-1 {
+		afterBuyDown = schedule[buyDown.Months].payment
+	}
+
+	better := model.BetterBuyDown
+	if regularCost < buyDownCost {
+		better = model.BetterDiscount
+	}
+
+	return &model.BuyDownComparison{
+		DiscountedPrice:     discountedPrice.Float(),
+		BuyDownPayment:      schedule[0].payment.Float(),
+		PaymentAfterBuyDown: afterBuyDown.Float(),
+		BuyDownTotalCost:    buyDownCost.Float(),
+		FullCostRate:        round3(irr(flows) * 12 * 100),
+		RegularLoanSum:      regular.loanSum.Float(),
+		RegularPayment:      regularSchedule[0].payment.Float(),
+		RegularTotalCost:    regularCost.Float(),
+		Savings:             (regularCost - buyDownCost).Float(),
+		Better:              better,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculateBuyDown(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name       string
+		buyDown    model.BuyDown
+		wantRate   float64
+		wantBetter string
+		wantAfter  bool
+	}{
+		{
+			name:       "whole term subsidy outweighs a moderate commission",
+			buyDown:    model.BuyDown{Rate: 0.1, Commission: 0.15},
+			wantRate:   0.1,
+			wantBetter: model.BetterBuyDown,
+		},
+		{
+			name:       "short subsidy does not pay for a large commission",
+			buyDown:    model.BuyDown{Rate: 6, Months: 24, Commission: 0.15},
+			wantRate:   6,
+			wantBetter: model.BetterDiscount,
+			wantAfter:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Base: true},
+				BuyDown:        &tt.buyDown,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := result.Aggregates.Rate; got != tt.wantRate {
+				t.Errorf("Expected rate %v, got %v", tt.wantRate, got)
+			}
+
+			comparison := result.BuyDown
+			if comparison == nil {
+				t.Fatal("Expected buy-down comparison")
+			}
+			if comparison.DiscountedPrice != 4_250_000 || comparison.RegularLoanSum != 3_250_000 {
+				t.Errorf("Expected discounted price 4250000 and loan 3250000, got %f and %f", comparison.DiscountedPrice, comparison.RegularLoanSum)
+			}
+			if comparison.Better != tt.wantBetter {
+				t.Errorf("Expected %s to be better, got %s", tt.wantBetter, comparison.Better)
+			}
+			if got := comparison.PaymentAfterBuyDown > comparison.BuyDownPayment; got != tt.wantAfter {
+				t.Errorf("Expected payment rise after the buy-down %v, got %+v", tt.wantAfter, comparison)
+			}
+
+			// The commission makes the buy-down dearer than its nominal rate
+			if comparison.FullCostRate <= tt.wantRate {
+				t.Errorf("Expected full cost rate above %v, got %v", tt.wantRate, comparison.FullCostRate)
+			}
+			if (comparison.Savings > 0) != (tt.wantBetter == model.BetterBuyDown) {
+				t.Errorf("Expected savings to match the better option, got %f", comparison.Savings)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateBuyDownCommissionTooLarge(t *testing.T) {
+	_, err := NewCalculator().Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Base: true},
+		BuyDown:        &model.BuyDown{Rate: 0.1, Commission: 0.8},
+	})
+	if !errors.Is(err, ErrBuyDownCommissionTooLarge) {
+		t.Errorf("Expected error %v, got %v", ErrBuyDownCommissionTooLarge, err)
+	}
+}
+
+func TestCalculator_CalculateBuyDownCombinedLoan(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 6_000_000, ExcessRate: 10},
+	}))
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     12_000_000,
+		InitialPayment: 2_400_000,
+		Months:         240,
+		Program:        model.MortgageProgram{ID: "family"},
+		BuyDown:        &model.BuyDown{Rate: 3, Commission: 0.1},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The regular loan of 8400000 is split at the limit as the buy-down one
+	rounding := money.DefaultRounding()
+	want := annuityPayment(6_000_000*money.Ruble, 6, 240, rounding) + annuityPayment(2_400_000*money.Ruble, 10, 240, rounding)
+	if got := result.BuyDown.RegularPayment; got != want.Float() {
+		t.Errorf("Expected combined regular payment %s, got %.2f", want, got)
+	}
+}
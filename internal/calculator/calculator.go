@@ -1,79 +1,426 @@
 package calculator
 
 import (
-	"math"
+	"context"
+	"fmt"
+	"mortgage-calculator/internal/calendar"
+	"mortgage-calculator/internal/keyrate"
 	"mortgage-calculator/internal/model"
-	"time"
+	"mortgage-calculator/internal/money"
+	"strings"
 )
 
 type Calculator interface {
 	Calculate(request *model.MortgageRequest) (*model.MortgageCalculation, error)
+	Affordability(request *model.AffordabilityRequest) (*model.AffordabilityResult, error)
+	Solve(request *model.SolveRequest) (*model.SolveResult, error)
+	Refinance(request *model.RefinanceRequest) (*model.RefinanceResult, error)
+	Simulate(ctx context.Context, request *model.SimulationRequest) (*model.SimulationResult, error)
+	Programs() []model.Program
 }
 
-type calculatorImpl struct{}
+type calculatorImpl struct {
+	programs map[string]model.Program
+	// catalog keeps the configured order of programs
+	catalog  []model.Program
+	rounding money.Rounding
+	keyRates *keyrate.History
+	calendar *calendar.Calendar
+	clock    Clock
+	// debtLoadLimits are the debt-to-income thresholds in percent
+	debtLoadLimits model.DebtLoadLimits
+}
+
+type Option func(*calculatorImpl)
+
+// WithPrograms replaces the default program catalog, an empty list keeps it.
+func WithPrograms(programs []model.Program) Option {
+	return func(c *calculatorImpl) {
+		if len(programs) == 0 {
+			return
+		}
+		c.catalog = programs
+		c.programs = make(map[string]model.Program, len(programs))
+		for _, program := range programs {
+			c.programs[program.ID] = program
+		}
+	}
+}
+
+// WithRounding sets the payment rounding policy.
+func WithRounding(rounding money.Rounding) Option {
+	return func(c *calculatorImpl) {
+		c.rounding = rounding
+	}
+}
+
+// WithKeyRates sets the key rate history used by floating rates.
+func WithKeyRates(history *keyrate.History) Option {
+	return func(c *calculatorImpl) {
+		c.keyRates = history
+	}
+}
+
+// WithCalendar moves payments falling on weekends and holidays to the next
+// working day.
+func WithCalendar(cal *calendar.Calendar) Option {
+	return func(c *calculatorImpl) {
+		c.calendar = cal
+	}
+}
+
+// WithDebtLoadLimits sets the debt-to-income thresholds.
+func WithDebtLoadLimits(limits model.DebtLoadLimits) Option {
+	return func(c *calculatorImpl) {
+		c.debtLoadLimits = limits
+	}
+}
+
+// WithClock sets the clock used when the request has no issue date.
+func WithClock(clock Clock) Option {
+	return func(c *calculatorImpl) {
+		c.clock = clock
+	}
+}
+
+func NewCalculator(opts ...Option) Calculator {
+	c := &calculatorImpl{
+		rounding:       money.DefaultRounding(),
+		clock:          systemClock{},
+		debtLoadLimits: DefaultDebtLoadLimits(),
+	}
+	WithPrograms(DefaultPrograms())(c)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
 
-func NewCalculator() Calculator {
-	return &calculatorImpl{}
+// DefaultPrograms is the catalog used when none is configured.
+func DefaultPrograms() []model.Program {
+	return []model.Program{
+		{ID: model.ProgramSalary, Name: "Salary project", Rate: 8, MinDownPayment: 0.2, LifeInsuranceSurcharge: 1,
+			Eligibility: &model.EligibilityRules{SalaryProject: true}},
+		{ID: model.ProgramMilitary, Name: "Military", Rate: 9, MinDownPayment: 0.2, LifeInsuranceSurcharge: 1,
+			Eligibility: &model.EligibilityRules{MilitaryService: true}},
+		{ID: model.ProgramBase, Name: "Base", Rate: 10, MinDownPayment: 0.2, LifeInsuranceSurcharge: 1},
+	}
 }
 
 func (c *calculatorImpl) Calculate(req *model.MortgageRequest) (*model.MortgageCalculation, error) {
-	// Validate initial payment (20%)
-	minInitialPayment := req.ObjectCost * 0.2
-	if req.InitialPayment < minInitialPayment {
+	program, err := c.program(req.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	// The borrower must qualify for the program when the profile is given
+	if req.Borrower != nil {
+		if reasons := ineligibilityReasons(program.Eligibility, req.Borrower); len(reasons) > 0 {
+			return nil, &EligibilityError{Reasons: reasons}
+		}
+	}
+
+	objectCost := money.FromFloat(req.ObjectCost)
+	initialPayment := money.FromFloat(req.InitialPayment)
+
+	// Subsidies add to the down payment or repay the principal later
+	subsidies, subsidyPrepayments := splitSubsidies(req.Subsidies)
+	downPayment := initialPayment + subsidies
+
+	// Validate initial payment against the program minimum
+	if !c.downPaymentCovers(objectCost, downPayment, program.MinDownPayment) {
 		return nil, ErrInitialPaymentTooLow
 	}
 
-	// Determine interest rate based on program
-	annualRate := c.getAnnualRate(req.Program)
-	monthlyRate := annualRate / 12 / 100
+	// Validate program limits
+	if program.MaxMonths > 0 && req.Months > program.MaxMonths {
+		return nil, ErrTermTooLong
+	}
 
 	// Calculate loan sum
-	loanSum := req.ObjectCost - req.InitialPayment
+	loanSum := objectCost - downPayment
+	if loanSum <= 0 {
+		if subsidies > 0 {
+			return nil, ErrSubsidiesExceedCost
+		}
+		return nil, ErrNoLoan
+	}
+	if program.MaxLoan > 0 && loanSum > money.FromFloat(program.MaxLoan) {
+		return nil, ErrLoanTooLarge
+	}
+
+	// The rate grid picks the tier by loan-to-value and term
+	baseRate, tier, err := c.programRate(program, loanSum, objectCost, req.Months)
+	if err != nil {
+		return nil, err
+	}
+
+	repaymentType := req.RepaymentType
+	if repaymentType == "" {
+		repaymentType = model.RepaymentAnnuity
+	}
+
+	// Refusing life insurance raises the program rate
+	surcharge := lifeInsuranceSurcharge(program, req.Insurance)
+	dates := c.loanDates(req.IssueDate, req.PaymentDay)
+	rates, err := c.rateTimeline(baseRate, surcharge, req.RateTimeline, req.Months, dates.issue)
+	if err != nil {
+		return nil, err
+	}
+	regularRates := rates
+	if req.BuyDown != nil {
+		rates = buyDownRates(rates, req.BuyDown.Rate+surcharge, req.BuyDown.Months)
+	}
+	annualRate := rates(1, dates.issue)
+
+	// Calculate annuity payment, differentiated loans have no fixed payment
+	var monthlyPayment money.Money
+	if repaymentType == model.RepaymentAnnuity {
+		monthlyPayment = annuityPayment(loanSum, annualRate, req.Months, c.rounding)
+	}
+
+	// Build schedule, overpayment is the sum of its interest parts
+	params := scheduleParams{
+		loanSum:       loanSum,
+		months:        req.Months,
+		repaymentType: repaymentType,
+		rates:         rates,
+		rounding:      c.rounding,
+		dates:         dates,
+		dayCount:      req.DayCount,
+	}
+	if req.Grace != nil {
+		if err := c.applyGrace(&params, req.Grace, annualRate); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Disbursements) > 0 {
+		// The tranches are sized for the loan at the undiscounted price
+		if req.BuyDown != nil {
+			return nil, ErrBuyDownWithDisbursements
+		}
+		if err := applyDisbursements(&params, req.Disbursements); err != nil {
+			return nil, err
+		}
+	}
+
+	// A loan above the subsidy limit is split into two tranches, the combined
+	// schedule does not support rate changes, prepayments, grace payments and
+	// construction tranches
+	var tranches []tranche
+	if limit := money.FromFloat(program.SubsidyLimit); limit > 0 && loanSum > limit {
+		if len(req.RateTimeline) > 0 || len(req.Prepayments) > 0 || len(subsidyPrepayments) > 0 || params.gracePayment > 0 || params.disbursements != nil {
+			return nil, ErrCombinedLoanUnsupported
+		}
+		tranches = splitLoan(params, limit, program.ExcessRate+surcharge)
+	}
 
-	// Calculate annuity payment
-	annuityCoeff := (monthlyRate * math.Pow(1+monthlyRate, float64(req.Months))) /
-		(math.Pow(1+monthlyRate, float64(req.Months)) - 1)
-	monthlyPayment := loanSum * annuityCoeff
+	var schedule []loanPeriod
+	if tranches != nil {
+		schedule = combinedSchedule(tranches)
+	} else {
+		schedule = buildSchedule(params)
+	}
+	overpayment := totalInterest(schedule)
 
-	// Calculate overpayment
-	totalPayment := monthlyPayment * float64(req.Months)
-	overpayment := totalPayment - loanSum
+	first, last := schedule[0], schedule[len(schedule)-1]
+	if repaymentType == model.RepaymentDifferentiated || tranches != nil {
+		// The first payment is the largest one for differentiated loans,
+		// for combined ones it is the sum of the tranche annuities
+		monthlyPayment = first.payment
+	}
 
-	// Calculate last payment date
-	lastPaymentDate := time.Now().AddDate(0, req.Months, 0)
+	// After the grace period the regular payment is the first amortizing one
+	var gracePayment, postGracePayment money.Money
+	if params.graceMonths > 0 && params.graceMonths < len(schedule) {
+		gracePayment = first.payment
+		postGracePayment = schedule[params.graceMonths].payment
+		monthlyPayment = postGracePayment
+	}
 
-	return &model.MortgageCalculation{
+	result := &model.MortgageCalculation{
 		Params: model.MortgageParams{
 			ObjectCost:     req.ObjectCost,
 			InitialPayment: req.InitialPayment,
 			Months:         req.Months,
 		},
-		Program: req.Program,
+		Program:  selectedProgram(req.Program, program.ID),
+		RateTier: tier,
+		// Without a profile the program rules are not checked
+		EligibilityUnverified: req.Borrower == nil && program.Eligibility != nil,
 		Aggregates: model.MortgageAggregates{
-			Rate:            annualRate,
-			LoanSum:         loanSum,
-			MonthlyPayment:  math.Round(monthlyPayment),
-			Overpayment:     math.Round(overpayment),
-			LastPaymentDate: lastPaymentDate,
+			Rate:                 annualRate,
+			LoanSum:              loanSum.Float(),
+			MonthlyPayment:       monthlyPayment.Float(),
+			Overpayment:          overpayment.Float(),
+			LastPaymentDate:      last.date,
+			RepaymentType:        repaymentType,
+			DayCount:             req.DayCount,
+			CalendarWarning:      dates.calendarWarning(first.date, last.date),
+			DownPaymentSubsidies: subsidies.Float(),
+			FirstPayment:         first.payment.Float(),
+			LastPayment:          last.payment.Float(),
+			GracePayment:         gracePayment.Float(),
+			PostGracePayment:     postGracePayment.Float(),
 		},
-	}, nil
+	}
+
+	// Debt load with the regular mortgage payment
+	if req.MonthlyIncome > 0 {
+		result.DebtLoad = c.debtLoad(money.FromFloat(req.MonthlyIncome), money.FromFloat(req.DebtPayments), monthlyPayment)
+	}
+
+	if tranches != nil {
+		result.Tranches = toModelTranches(tranches, params.graceMonths)
+	}
+
+	// The developer buy-down against the discounted price at regular rates
+	if req.BuyDown != nil {
+		limit := money.FromFloat(program.SubsidyLimit)
+		if result.BuyDown, err = compareBuyDown(params, regularRates, limit, program.ExcessRate+surcharge, req.BuyDown, objectCost, downPayment, schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.RateTimeline) > 0 {
+		result.RateSegments = rateSegments(schedule)
+	}
+
+	// Recalculate the loan with early repayments
+	if len(req.Prepayments) > 0 || len(subsidyPrepayments) > 0 {
+		all := append(subsidyPrepayments, req.Prepayments...)
+		prepayments, err := schedulePrepayments(all, req.Months, dates)
+		if err != nil {
+			return nil, err
+		}
+
+		params.prepayments = prepayments
+		schedule = buildSchedule(params)
+		result.EarlyRepayment = earlyRepayment(schedule, overpayment)
+	}
+
+	// Payment holiday on top of the actual schedule
+	if req.Holiday != nil {
+		if schedule, result.Holiday, err = applyHoliday(schedule, req.Holiday, dates); err != nil {
+			return nil, err
+		}
+	}
+
+	// Full cost of credit over the actual cash flows
+	premiums := insurancePremiums(loanSum, schedule, req.Insurance, c.rounding.Mode)
+	cost := fullCreditCost(loanSum, schedule, req.Fees, premiums)
+	result.Aggregates.EffectiveRate = cost.effectiveRate
+	result.Aggregates.FullCostRate = cost.fullCostRate
+	result.Aggregates.FeesTotal = cost.fees.Float()
+	result.Aggregates.InsuranceCost = cost.insurance.Float()
+	result.Aggregates.RateSurcharge = surcharge
+
+	if params.disbursements != nil {
+		result.CashFlows = cashFlows(loanSum, schedule, dates)
+	}
+
+	if req.Schedule {
+		result.Schedule = toModelSchedule(schedule)
+	}
+
+	return result, nil
 }
 
-func (c *calculatorImpl) getAnnualRate(program model.MortgageProgram) float64 {
-	switch {
-	case program.Salary:
-		return 8
-	case program.Military:
-		return 9
-	case program.Base:
-		return 10
-	default:
-		return 0
+// Programs returns the catalog in the configured order.
+func (c *calculatorImpl) Programs() []model.Program {
+	return c.catalog
+}
+
+// program resolves the requested program in the catalog.
+func (c *calculatorImpl) program(requested model.MortgageProgram) (model.Program, error) {
+	program, ok := c.programs[requested.ProgramID()]
+	if !ok {
+		return model.Program{}, ErrUnknownProgram
+	}
+	return program, nil
+}
+
+// downPaymentCovers reports whether the initial payment reaches the minimum
+// share of the object cost.
+func (c *calculatorImpl) downPaymentCovers(objectCost, initialPayment money.Money, share float64) bool {
+	return initialPayment >= objectCost.Percent(share, 100, 1, c.rounding.Mode)
+}
+
+// applyGrace adds the grace period to the schedule params. A reduced grace
+// payment must cover the interest, otherwise the debt would grow.
+func (c *calculatorImpl) applyGrace(params *scheduleParams, grace *model.GracePeriod, annualRate float64) error {
+	if grace.Months >= params.months {
+		return ErrGraceTooLong
+	}
+
+	payment := money.FromFloat(grace.Payment)
+	if payment > 0 && payment < params.loanSum.Percent(annualRate, 1, 12, c.rounding.Mode) {
+		return ErrGracePaymentBelowInterest
+	}
+
+	params.graceMonths = grace.Months
+	params.gracePayment = payment
+	return nil
+}
+
+// selectedProgram echoes the requested program with its resolved ID.
+func selectedProgram(requested model.MortgageProgram, id string) model.MortgageProgram {
+	requested.ID = id
+	return requested
+}
+
+// earlyRepayment summarizes the recalculated schedule against the baseline overpayment.
+func earlyRepayment(schedule []loanPeriod, baselineOverpayment money.Money) *model.EarlyRepayment {
+	last := schedule[len(schedule)-1]
+	overpayment := totalInterest(schedule)
+
+	// The regular payment after the last prepayment, the closing payment
+	// is skipped as it only settles the remaining balance
+	monthlyPayment := schedule[0].payment
+	for i := 0; i+2 < len(schedule); i++ {
+		if schedule[i].prepayment > 0 {
+			monthlyPayment = schedule[i+1].payment
+		}
+	}
+
+	return &model.EarlyRepayment{
+		Months:          last.number,
+		MonthlyPayment:  monthlyPayment.Float(),
+		TotalPrepaid:    totalPrepaid(schedule).Float(),
+		Overpayment:     overpayment.Float(),
+		InterestSaved:   (baselineOverpayment - overpayment).Float(),
+		LastPaymentDate: last.date,
 	}
 }
 
 var (
-	ErrInitialPaymentTooLow = &BusinessError{"the initial payment should be more"}
+	ErrInitialPaymentTooLow      = &BusinessError{"initial payment too low"}
+	ErrPrepaymentWithoutDate     = &BusinessError{"prepayment month or date is required"}
+	ErrPrepaymentOutOfTerm       = &BusinessError{"prepayment is outside the loan term"}
+	ErrUnknownProgram            = &BusinessError{"unknown program"}
+	ErrTermTooLong               = &BusinessError{"loan term exceeds the program maximum"}
+	ErrLoanTooLarge              = &BusinessError{"loan sum exceeds the program maximum"}
+	ErrNotAffordable             = &BusinessError{"no loan fits the budget"}
+	ErrUnknownSolveTarget        = &BusinessError{"unknown solve target"}
+	ErrInvalidRateChange         = &BusinessError{"rate change needs either a rate or a spread"}
+	ErrRateChangeOutOfTerm       = &BusinessError{"rate change is outside the loan term"}
+	ErrKeyRateUnavailable        = &BusinessError{"key rate is not available for a floating rate"}
+	ErrGraceTooLong              = &BusinessError{"grace period must be shorter than the loan term"}
+	ErrGracePaymentBelowInterest = &BusinessError{"grace payment does not cover the interest"}
+	ErrNoLoan                    = &BusinessError{"initial payment covers the whole object cost"}
+	ErrSubsidiesExceedCost       = &BusinessError{"down payment with subsidies covers the whole object cost"}
+	ErrNotEligible               = &BusinessError{"borrower is not eligible for the program"}
+	ErrNoRateTier                = &BusinessError{"loan-to-value or term is outside the program rate grid"}
+	ErrObjectCostRequired        = &BusinessError{"object cost is required for the program rate grid"}
+	ErrBuyDownCommissionTooLarge = &BusinessError{"buy-down commission leaves no loan at the discounted price"}
+	ErrGraceWithDisbursements    = &BusinessError{"grace period cannot be combined with disbursements"}
+	ErrBuyDownWithDisbursements  = &BusinessError{"developer buy-down cannot be combined with disbursements"}
+	ErrDisbursementsMismatch     = &BusinessError{"disbursements must add up to the loan sum"}
+	ErrDisbursementOutOfTerm     = &BusinessError{"disbursements must end before the last payment"}
+	ErrHolidayOutOfTerm          = &BusinessError{"payment holiday must end before the last payment"}
+	ErrSimulationTooLarge        = &BusinessError{"simulation paths times months must not exceed 1000000"}
+	ErrSimulationTimeout         = &BusinessError{"simulation did not finish in time, reduce paths or months"}
+	ErrCombinedLoanUnsupported   = &BusinessError{"rate timeline, prepayments, grace payments and disbursements are not supported above the subsidy limit"}
 )
 
 type BusinessError struct {
@@ -83,3 +430,17 @@ type BusinessError struct {
 func (e *BusinessError) Error() string {
 	return e.Message
 }
+
+// EligibilityError is ErrNotEligible with the failed rules, the reasons are
+// the codes of model.IneligibleProgram.
+type EligibilityError struct {
+	Reasons []string
+}
+
+func (e *EligibilityError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrNotEligible.Message, strings.Join(e.Reasons, ", "))
+}
+
+func (e *EligibilityError) Unwrap() error {
+	return ErrNotEligible
+}
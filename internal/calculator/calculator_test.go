@@ -1,7 +1,10 @@
 package calculator
 
 import (
+	"errors"
+	"math"
 	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
 	"testing"
 )
 
@@ -34,6 +37,26 @@ func TestCalculator_Calculate(t *testing.T) {
 			wantPayment: 0,
 			wantError:   ErrInitialPaymentTooLow,
 		},
+		{
+			name: "initial payment equals the object cost",
+			request: &model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 5_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+			},
+			wantError: ErrNoLoan,
+		},
+		{
+			name: "initial payment above the object cost",
+			request: &model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 6_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+			},
+			wantError: ErrNoLoan,
+		},
 	}
 
 	calc := NewCalculator()
@@ -60,3 +83,421 @@ func TestCalculator_Calculate(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculator_CalculateSchedule(t *testing.T) {
+	calc := NewCalculator()
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		Schedule:       true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Schedule) != 240 {
+		t.Fatalf("Expected 240 periods, got %d", len(result.Schedule))
+	}
+
+	var principal, interest float64
+	for _, p := range result.Schedule {
+		principal += p.Principal
+		interest += p.Interest
+	}
+
+	if math.Abs(principal-result.Aggregates.LoanSum) > 0.005 {
+		t.Errorf("Principal parts sum %f, want loan sum %f", principal, result.Aggregates.LoanSum)
+	}
+	if math.Abs(interest-result.Aggregates.Overpayment) > 0.005 {
+		t.Errorf("Interest parts sum %f, want overpayment %f", interest, result.Aggregates.Overpayment)
+	}
+
+	last := result.Schedule[len(result.Schedule)-1]
+	if last.Balance != 0 {
+		t.Errorf("Expected zero balance after last payment, got %f", last.Balance)
+	}
+	if !last.Date.Equal(result.Aggregates.LastPaymentDate) {
+		t.Errorf("Expected last period date %v, got %v", result.Aggregates.LastPaymentDate, last.Date)
+	}
+
+	withoutSchedule, _ := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+	})
+	if withoutSchedule.Schedule != nil {
+		t.Errorf("Expected no schedule when it was not requested")
+	}
+}
+
+func TestCalculator_CalculateDifferentiated(t *testing.T) {
+	calc := NewCalculator()
+
+	request := &model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		RepaymentType:  model.RepaymentDifferentiated,
+		Schedule:       true,
+	}
+
+	result, err := calc.Calculate(request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	aggregates := result.Aggregates
+	if aggregates.FirstPayment != 43333.34 {
+		t.Errorf("Expected first payment 43333.34, got %f", aggregates.FirstPayment)
+	}
+	if aggregates.MonthlyPayment != aggregates.FirstPayment {
+		t.Errorf("Expected monthly payment to equal first payment, got %f", aggregates.MonthlyPayment)
+	}
+	if aggregates.LastPayment >= aggregates.FirstPayment {
+		t.Errorf("Expected declining payments, first %f, last %f", aggregates.FirstPayment, aggregates.LastPayment)
+	}
+
+	// Overpayment of a differentiated loan is rate * loan * (n + 1) / 2
+	wantOverpayment := 8.0 / 12 / 100 * 4_000_000 * 241 / 2
+	if math.Abs(aggregates.Overpayment-wantOverpayment) > 1 {
+		t.Errorf("Expected overpayment %f, got %f", wantOverpayment, aggregates.Overpayment)
+	}
+
+	request.RepaymentType = model.RepaymentAnnuity
+	annuity, _ := calc.Calculate(request)
+	if aggregates.Overpayment >= annuity.Aggregates.Overpayment {
+		t.Errorf("Expected differentiated overpayment %f below annuity %f", aggregates.Overpayment, annuity.Aggregates.Overpayment)
+	}
+}
+
+func TestCalculator_CalculatePrepayments(t *testing.T) {
+	tests := []struct {
+		name        string
+		prepayments []model.Prepayment
+		wantMonths  int
+		wantPayment float64
+		wantError   error
+	}{
+		{
+			name:        "one-off reduce term",
+			prepayments: []model.Prepayment{{Month: 12, Amount: 500_000, Strategy: model.PrepaymentReduceTerm}},
+			wantMonths:  184,
+			wantPayment: 33458,
+		},
+		{
+			name:        "one-off reduce payment",
+			prepayments: []model.Prepayment{{Month: 12, Amount: 500_000, Strategy: model.PrepaymentReducePayment}},
+			wantMonths:  240,
+			wantPayment: 29185,
+		},
+		{
+			name:        "recurring reduce term",
+			prepayments: []model.Prepayment{{Month: 1, Amount: 10_000, Strategy: model.PrepaymentReduceTerm, EveryMonths: 1}},
+			wantMonths:  144,
+			wantPayment: 33458,
+		},
+		{
+			name:        "prepayment without month or date",
+			prepayments: []model.Prepayment{{Amount: 10_000, Strategy: model.PrepaymentReduceTerm}},
+			wantError:   ErrPrepaymentWithoutDate,
+		},
+		{
+			name:        "prepayment after the last payment",
+			prepayments: []model.Prepayment{{Month: 241, Amount: 10_000, Strategy: model.PrepaymentReduceTerm}},
+			wantError:   ErrPrepaymentOutOfTerm,
+		},
+	}
+
+	calc := NewCalculator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Prepayments:    tt.prepayments,
+				Schedule:       true,
+			})
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			early := result.EarlyRepayment
+			if early.Months != tt.wantMonths {
+				t.Errorf("Expected %d months, got %d", tt.wantMonths, early.Months)
+			}
+			if early.MonthlyPayment != tt.wantPayment {
+				t.Errorf("Expected payment %f, got %f", tt.wantPayment, early.MonthlyPayment)
+			}
+			if early.InterestSaved <= 0 {
+				t.Errorf("Expected saved interest, got %f", early.InterestSaved)
+			}
+
+			var repaid float64
+			for _, p := range result.Schedule {
+				repaid += p.Principal + p.Prepayment
+			}
+			if math.Abs(repaid-result.Aggregates.LoanSum) > 0.005 {
+				t.Errorf("Repaid %f, want loan sum %f", repaid, result.Aggregates.LoanSum)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateProgramCatalog(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, MaxMonths: 360, MaxLoan: 6_000_000},
+		{ID: model.ProgramSalary, Rate: 7, MinDownPayment: 0.3},
+	}))
+
+	tests := []struct {
+		name      string
+		request   *model.MortgageRequest
+		wantRate  float64
+		wantError error
+	}{
+		{
+			name:     "program by id",
+			request:  &model.MortgageRequest{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 240, Program: model.MortgageProgram{ID: "family"}},
+			wantRate: 6,
+		},
+		{
+			name:     "legacy flag resolves to catalog program",
+			request:  &model.MortgageRequest{ObjectCost: 5_000_000, InitialPayment: 1_500_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantRate: 7,
+		},
+		{
+			name:      "program minimum down payment",
+			request:   &model.MortgageRequest{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantError: ErrInitialPaymentTooLow,
+		},
+		{
+			name:      "program not in catalog",
+			request:   &model.MortgageRequest{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 240, Program: model.MortgageProgram{Base: true}},
+			wantError: ErrUnknownProgram,
+		},
+		{
+			name:      "term above program maximum",
+			request:   &model.MortgageRequest{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 480, Program: model.MortgageProgram{ID: "family"}},
+			wantError: ErrTermTooLong,
+		},
+		{
+			name:      "loan above program maximum",
+			request:   &model.MortgageRequest{ObjectCost: 10_000_000, InitialPayment: 2_000_000, Months: 240, Program: model.MortgageProgram{ID: "family"}},
+			wantError: ErrLoanTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(tt.request)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Aggregates.Rate != tt.wantRate {
+				t.Errorf("Expected rate %f, got %f", tt.wantRate, result.Aggregates.Rate)
+			}
+			if result.Program.ID != tt.request.Program.ProgramID() {
+				t.Errorf("Expected program %s, got %s", tt.request.Program.ProgramID(), result.Program.ID)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateRounding(t *testing.T) {
+	tests := []struct {
+		name        string
+		rounding    money.Rounding
+		wantPayment float64
+	}{
+		{"rubles", money.Rounding{Unit: "ruble", Mode: money.HalfUp}, 33458},
+		{"kopecks", money.Rounding{Unit: "kopeck", Mode: money.HalfUp}, 33457.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calc := NewCalculator(WithRounding(tt.rounding))
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Schedule:       true,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Aggregates.MonthlyPayment != tt.wantPayment {
+				t.Errorf("Expected monthly payment %f, got %f", tt.wantPayment, result.Aggregates.MonthlyPayment)
+			}
+
+			// Payments reconcile with the loan sum and overpayment to the kopeck
+			var paid money.Money
+			for _, p := range result.Schedule[:len(result.Schedule)-1] {
+				if p.Payment != tt.wantPayment {
+					t.Fatalf("Expected regular payment %f in period %d, got %f", tt.wantPayment, p.Number, p.Payment)
+				}
+				paid += money.FromFloat(p.Payment)
+			}
+			paid += money.FromFloat(result.Aggregates.LastPayment)
+
+			want := money.FromFloat(result.Aggregates.LoanSum) + money.FromFloat(result.Aggregates.Overpayment)
+			if paid != want {
+				t.Errorf("Payments sum %s, want loan sum plus overpayment %s", paid, want)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateZeroRate(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "zero", Rate: 0, MinDownPayment: 0.2},
+		{ID: "subsidized", Rate: 0.01, MinDownPayment: 0.2},
+	}))
+
+	tests := []struct {
+		name            string
+		program         model.MortgageProgram
+		wantPayment     float64
+		wantOverpayment float64
+		wantError       error
+	}{
+		{
+			name:            "zero rate is repaid in equal parts",
+			program:         model.MortgageProgram{ID: "zero"},
+			wantPayment:     16667,
+			wantOverpayment: 0,
+		},
+		{
+			name:            "near-zero subsidized rate",
+			program:         model.MortgageProgram{ID: "subsidized"},
+			wantPayment:     16683,
+			wantOverpayment: 4018.1,
+		},
+		{
+			name:      "no program selected",
+			program:   model.MortgageProgram{},
+			wantError: ErrUnknownProgram,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        tt.program,
+			})
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Aggregates.MonthlyPayment != tt.wantPayment {
+				t.Errorf("Expected monthly payment %f, got %f", tt.wantPayment, result.Aggregates.MonthlyPayment)
+			}
+			if result.Aggregates.Overpayment != tt.wantOverpayment {
+				t.Errorf("Expected overpayment %f, got %f", tt.wantOverpayment, result.Aggregates.Overpayment)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateGrace(t *testing.T) {
+	tests := []struct {
+		name          string
+		grace         *model.GracePeriod
+		wantGrace     float64
+		wantPostGrace float64
+		wantError     error
+	}{
+		{
+			name:          "interest only",
+			grace:         &model.GracePeriod{Months: 12},
+			wantGrace:     26666.67,
+			wantPostGrace: 34180,
+		},
+		{
+			name:          "reduced payment",
+			grace:         &model.GracePeriod{Months: 12, Payment: 30_000},
+			wantGrace:     30_000,
+			wantPostGrace: 33825,
+		},
+		{
+			name:      "reduced payment below interest",
+			grace:     &model.GracePeriod{Months: 12, Payment: 20_000},
+			wantError: ErrGracePaymentBelowInterest,
+		},
+		{
+			name:      "grace as long as the term",
+			grace:     &model.GracePeriod{Months: 240},
+			wantError: ErrGraceTooLong,
+		},
+	}
+
+	calc := NewCalculator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Grace:          tt.grace,
+				Schedule:       true,
+			})
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			aggregates := result.Aggregates
+			if aggregates.GracePayment != tt.wantGrace {
+				t.Errorf("Expected grace payment %f, got %f", tt.wantGrace, aggregates.GracePayment)
+			}
+			if aggregates.PostGracePayment != tt.wantPostGrace || aggregates.MonthlyPayment != tt.wantPostGrace {
+				t.Errorf("Expected post-grace payment %f, got %f (monthly %f)", tt.wantPostGrace, aggregates.PostGracePayment, aggregates.MonthlyPayment)
+			}
+			if len(result.Schedule) != 240 || result.Schedule[239].Balance != 0 {
+				t.Errorf("Expected the loan to be repaid in 240 months")
+			}
+		})
+	}
+}
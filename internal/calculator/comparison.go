@@ -0,0 +1,93 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"sort"
+)
+
+// Compare calculates the params for every program of the calculator catalog
+// and ranks the programs the params qualify for. Without a borrower the
+// programs with eligibility rules are offered as unverified, as Calculate
+// does.
+func Compare(calc Calculator, req *model.CompareRequest) (*model.Comparison, error) {
+	comparison := &model.Comparison{
+		Params:        req.MortgageParams,
+		Offers:        []model.ProgramOffer{},
+		ByOverpayment: []string{},
+	}
+
+	for _, program := range calc.Programs() {
+		result, err := calc.Calculate(&model.MortgageRequest{
+			ObjectCost:     req.ObjectCost,
+			InitialPayment: req.InitialPayment,
+			Months:         req.Months,
+			Program:        model.MortgageProgram{ID: program.ID},
+			Borrower:       req.Borrower,
+		})
+		if err != nil {
+			var businessErr *BusinessError
+			if !errors.As(err, &businessErr) {
+				return nil, err
+			}
+			rejected := model.RejectedProgram{ProgramID: program.ID, ProgramName: program.Name, Reason: businessErr.Message}
+			var eligibilityErr *EligibilityError
+			if errors.As(err, &eligibilityErr) {
+				rejected.Reasons = eligibilityErr.Reasons
+			}
+			comparison.Rejected = append(comparison.Rejected, rejected)
+			continue
+		}
+
+		comparison.Offers = append(comparison.Offers, model.ProgramOffer{
+			ProgramID:   program.ID,
+			ProgramName: program.Name,
+			Calculation: result,
+		})
+	}
+
+	if len(comparison.Offers) == 0 {
+		return comparison, nil
+	}
+
+	rankOffers(comparison)
+	return comparison, nil
+}
+
+// rankOffers sorts offers by monthly payment and fills ranks and differences.
+func rankOffers(comparison *model.Comparison) {
+	offers := comparison.Offers
+	payment := func(i int) money.Money {
+		return money.FromFloat(offers[i].Calculation.Aggregates.MonthlyPayment)
+	}
+	overpayment := func(i int) money.Money {
+		return money.FromFloat(offers[i].Calculation.Aggregates.Overpayment)
+	}
+
+	// Ranking by overpayment
+	byOverpayment := make([]int, len(offers))
+	for i := range byOverpayment {
+		byOverpayment[i] = i
+	}
+	sort.SliceStable(byOverpayment, func(a, b int) bool {
+		return overpayment(byOverpayment[a]) < overpayment(byOverpayment[b])
+	})
+	bestOverpayment := overpayment(byOverpayment[0])
+	for rank, i := range byOverpayment {
+		offers[i].OverpaymentRank = rank + 1
+		offers[i].OverpaymentDifference = (overpayment(i) - bestOverpayment).Float()
+	}
+
+	// Ranking by monthly payment defines the order of offers
+	sort.SliceStable(offers, func(a, b int) bool {
+		return payment(a) < payment(b)
+	})
+	bestPayment := payment(0)
+	comparison.ByOverpayment = make([]string, len(offers))
+	for i := range offers {
+		offers[i].PaymentRank = i + 1
+		offers[i].PaymentDifference = (payment(i) - bestPayment).Float()
+		comparison.ByOverpayment[offers[i].OverpaymentRank-1] = offers[i].ProgramID
+	}
+}
@@ -0,0 +1,127 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: model.ProgramBase, Name: "Base", Rate: 10, MinDownPayment: 0.2},
+		{ID: "family", Name: "Family", Rate: 6, MinDownPayment: 0.2, MaxLoan: 3_000_000},
+		{ID: model.ProgramSalary, Name: "Salary", Rate: 8, MinDownPayment: 0.2},
+		{ID: model.ProgramMilitary, Name: "Military", Rate: 9, MinDownPayment: 0.2, MaxMonths: 180},
+	}))
+
+	comparison, err := Compare(calc, &model.CompareRequest{MortgageParams: model.MortgageParams{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wantOrder := []string{model.ProgramSalary, model.ProgramBase}
+	if len(comparison.Offers) != len(wantOrder) {
+		t.Fatalf("Expected %d offers, got %d", len(wantOrder), len(comparison.Offers))
+	}
+	for i, id := range wantOrder {
+		offer := comparison.Offers[i]
+		if offer.ProgramID != id || offer.PaymentRank != i+1 || comparison.ByOverpayment[i] != id {
+			t.Errorf("Expected %s at rank %d, got %s (payment rank %d, overpayment ranking %v)",
+				id, i+1, offer.ProgramID, offer.PaymentRank, comparison.ByOverpayment)
+		}
+	}
+
+	best, second := comparison.Offers[0], comparison.Offers[1]
+	if best.PaymentDifference != 0 || best.OverpaymentDifference != 0 {
+		t.Errorf("Expected zero differences for the best offer, got %f and %f", best.PaymentDifference, best.OverpaymentDifference)
+	}
+	wantDifference := second.Calculation.Aggregates.MonthlyPayment - best.Calculation.Aggregates.MonthlyPayment
+	if second.PaymentDifference != wantDifference {
+		t.Errorf("Expected payment difference %f, got %f", wantDifference, second.PaymentDifference)
+	}
+
+	rejected := map[string]string{}
+	for _, r := range comparison.Rejected {
+		rejected[r.ProgramID] = r.Reason
+	}
+	if rejected["family"] != ErrLoanTooLarge.Message || rejected[model.ProgramMilitary] != ErrTermTooLong.Message {
+		t.Errorf("Unexpected rejected programs: %v", rejected)
+	}
+}
+
+func TestCompare_RestrictedPrograms(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: model.ProgramBase, Name: "Base", Rate: 10, MinDownPayment: 0.2},
+		{ID: model.ProgramMilitary, Name: "Military", Rate: 6, MinDownPayment: 0.2,
+			Eligibility: &model.EligibilityRules{MilitaryService: true}},
+	}))
+	params := model.MortgageParams{ObjectCost: 5_000_000, InitialPayment: 1_000_000, Months: 240}
+
+	tests := []struct {
+		name           string
+		borrower       *model.BorrowerProfile
+		wantOffers     []string
+		wantUnverified bool
+		wantReason     string
+		wantReasons    []string
+	}{
+		{
+			name:           "without borrower",
+			wantOffers:     []string{model.ProgramMilitary, model.ProgramBase},
+			wantUnverified: true,
+		},
+		{
+			name:        "ineligible borrower",
+			borrower:    &model.BorrowerProfile{Age: 30, Region: "77", PropertyType: model.PropertySecondary},
+			wantOffers:  []string{model.ProgramBase},
+			wantReason:  ErrNotEligible.Message,
+			wantReasons: []string{model.ReasonMilitaryServiceRequired},
+		},
+		{
+			name:       "eligible borrower",
+			borrower:   &model.BorrowerProfile{Age: 30, MilitaryService: true, Region: "77", PropertyType: model.PropertySecondary},
+			wantOffers: []string{model.ProgramMilitary, model.ProgramBase},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison, err := Compare(calc, &model.CompareRequest{MortgageParams: params, Borrower: tt.borrower})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var offers []string
+			for _, offer := range comparison.Offers {
+				offers = append(offers, offer.ProgramID)
+			}
+			if !reflect.DeepEqual(offers, tt.wantOffers) {
+				t.Errorf("Expected offers %v, got %v", tt.wantOffers, offers)
+			}
+			for _, offer := range comparison.Offers {
+				want := tt.wantUnverified && offer.ProgramID == model.ProgramMilitary
+				if got := offer.Calculation.EligibilityUnverified; got != want {
+					t.Errorf("Expected %s eligibility unverified %v, got %v", offer.ProgramID, want, got)
+				}
+			}
+
+			if tt.wantReason == "" {
+				if len(comparison.Rejected) != 0 {
+					t.Errorf("Expected no rejected programs, got %v", comparison.Rejected)
+				}
+				return
+			}
+			if len(comparison.Rejected) != 1 {
+				t.Fatalf("Expected one rejected program, got %v", comparison.Rejected)
+			}
+			rejected := comparison.Rejected[0]
+			if rejected.ProgramID != model.ProgramMilitary || rejected.Reason != tt.wantReason || !reflect.DeepEqual(rejected.Reasons, tt.wantReasons) {
+				t.Errorf("Unexpected rejected program: %+v", rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// applyDisbursements adds construction tranches to the schedule params. The
+// months up to the last tranche are interest only, like a grace period.
+func applyDisbursements(params *scheduleParams, disbursements []model.Disbursement) error {
+	if params.graceMonths > 0 {
+		return ErrGraceWithDisbursements
+	}
+
+	scheduled := make(map[int]money.Money)
+	var total money.Money
+	last := 0
+	for _, disbursement := range disbursements {
+		amount := money.FromFloat(disbursement.Amount)
+		total += amount
+		last = max(last, disbursement.Month)
+		if disbursement.Month > 0 {
+			scheduled[disbursement.Month] += amount
+		}
+	}
+
+	if total != params.loanSum {
+		return ErrDisbursementsMismatch
+	}
+	if last >= params.months {
+		return ErrDisbursementOutOfTerm
+	}
+
+	params.disbursements = scheduled
+	params.graceMonths = last
+	return nil
+}
+
+// cashFlows returns the tranches and payments of a construction loan
+// starting with the tranche released at issue.
+func cashFlows(loanSum money.Money, schedule []loanPeriod, dates paymentDates) []model.CashFlow {
+	issued := loanSum
+	for _, p := range schedule {
+		issued -= p.disbursement
+	}
+
+	flows := make([]model.CashFlow, 0, len(schedule)+1)
+	flows = append(flows, model.CashFlow{
+		Date:         dates.date(0),
+		Disbursement: issued.Float(),
+		Balance:      issued.Float(),
+	})
+	for _, p := range schedule {
+		flows = append(flows, model.CashFlow{
+			Number:       p.number,
+			Date:         p.date,
+			Disbursement: p.disbursement.Float(),
+			Payment:      (p.payment + p.prepayment).Float(),
+			Balance:      p.balance.Float(),
+		})
+	}
+	return flows
+}
@@ -0,0 +1,114 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculateConstructionLoan(t *testing.T) {
+	calc := NewCalculator()
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		Disbursements: []model.Disbursement{
+			{Month: 0, Amount: 1_000_000},
+			{Month: 6, Amount: 1_500_000},
+			{Month: 12, Amount: 1_500_000},
+		},
+		Schedule: true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Interest only on the disbursed amount during construction
+	for i, want := range map[int]float64{0: 6666.67, 5: 6666.67, 6: 16666.67, 11: 16666.67} {
+		if got := result.Schedule[i].Payment; got != want {
+			t.Errorf("Payment %d: expected %.2f, got %.2f", i+1, want, got)
+		}
+		if got := result.Schedule[i].Principal; got != 0 {
+			t.Errorf("Payment %d: expected no principal, got %.2f", i+1, got)
+		}
+	}
+
+	// The annuity starts after the last tranche
+	annuity := annuityPayment(money.FromFloat(4_000_000), 8, 228, money.DefaultRounding()).Float()
+	if got := result.Aggregates.MonthlyPayment; got != annuity {
+		t.Errorf("Expected monthly payment %.2f, got %.2f", annuity, got)
+	}
+	if got := result.Schedule[12].Payment; got != annuity {
+		t.Errorf("Expected payment 13 of %.2f, got %.2f", annuity, got)
+	}
+
+	flows := result.CashFlows
+	if len(flows) != 241 {
+		t.Fatalf("Expected 241 cash flows, got %d", len(flows))
+	}
+	var disbursed money.Money
+	for _, flow := range flows {
+		disbursed += money.FromFloat(flow.Disbursement)
+	}
+	if flows[0].Disbursement != 1_000_000 || disbursed != money.FromFloat(4_000_000) {
+		t.Errorf("Expected 1000000 at issue and 4000000 in total, got %.2f and %s", flows[0].Disbursement, disbursed)
+	}
+	if got := flows[len(flows)-1].Balance; got != 0 {
+		t.Errorf("Expected the loan to be repaid, got balance %.2f", got)
+	}
+}
+
+func TestCalculator_CalculateConstructionLoanErrors(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name          string
+		disbursements []model.Disbursement
+		grace         *model.GracePeriod
+		buyDown       *model.BuyDown
+		wantErr       error
+	}{
+		{
+			name:          "tranches do not add up",
+			disbursements: []model.Disbursement{{Month: 0, Amount: 1_000_000}, {Month: 6, Amount: 2_000_000}},
+			wantErr:       ErrDisbursementsMismatch,
+		},
+		{
+			name:          "last tranche at the end of the term",
+			disbursements: []model.Disbursement{{Month: 0, Amount: 1_000_000}, {Month: 240, Amount: 3_000_000}},
+			wantErr:       ErrDisbursementOutOfTerm,
+		},
+		{
+			name:          "tranches with a grace period",
+			disbursements: []model.Disbursement{{Month: 0, Amount: 1_000_000}, {Month: 6, Amount: 3_000_000}},
+			grace:         &model.GracePeriod{Months: 12},
+			wantErr:       ErrGraceWithDisbursements,
+		},
+		{
+			name:          "tranches with a developer buy-down",
+			disbursements: []model.Disbursement{{Month: 0, Amount: 1_000_000}, {Month: 6, Amount: 3_000_000}},
+			buyDown:       &model.BuyDown{Rate: 3, Commission: 0.5},
+			wantErr:       ErrBuyDownWithDisbursements,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Disbursements:  tt.disbursements,
+				Grace:          tt.grace,
+				BuyDown:        tt.buyDown,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
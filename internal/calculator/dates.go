@@ -0,0 +1,116 @@
+package calculator
+
+import (
+	"fmt"
+	"mortgage-calculator/internal/calendar"
+	"mortgage-calculator/internal/model"
+	"time"
+)
+
+// Clock is the source of the current time, replaced in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// paymentDates generates the payment dates of a loan. Payment number n is
+// due in the n-th month after issue on the payment day, clamped to the last
+// day of shorter months, so Jan 31 is followed by Feb 28 and Mar 31.
+// With a calendar, payments falling on days off move to the next working day.
+type paymentDates struct {
+	issue    time.Time
+	day      int
+	calendar *calendar.Calendar
+}
+
+// loanDates returns the payment dates for the request. The loan is issued on
+// the requested date or today, payments are due on the issue day by default.
+func (c *calculatorImpl) loanDates(issueDate *model.Date, paymentDay int) paymentDates {
+	var issue time.Time
+	if issueDate != nil {
+		issue = issueDate.Time
+	} else {
+		now := c.clock.Now()
+		issue = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	if paymentDay == 0 {
+		paymentDay = issue.Day()
+	}
+
+	return paymentDates{issue: issue, day: paymentDay, calendar: c.calendar}
+}
+
+// date returns the due date of the payment, number 0 is the issue date.
+func (d paymentDates) date(number int) time.Time {
+	if number == 0 {
+		return d.issue
+	}
+
+	// The first day of the target month never overflows
+	month := time.Date(d.issue.Year(), d.issue.Month()+time.Month(number), 1, 0, 0, 0, 0, d.issue.Location())
+	day := min(d.day, daysIn(month))
+	due := month.AddDate(0, 0, day-1)
+
+	// The shift never affects the following dates, they are counted from issue
+	if d.calendar != nil {
+		due = d.calendar.NextWorkday(due)
+	}
+	return due
+}
+
+// calendarWarning describes payment dates between first and last that are
+// outside the calendar. Holidays of those years are unknown, the payments
+// are moved over weekends only.
+func (d paymentDates) calendarWarning(first, last time.Time) string {
+	if d.calendar == nil || d.calendar.Covers(first) && d.calendar.Covers(last) {
+		return ""
+	}
+
+	from, to := d.calendar.Range()
+	return fmt.Sprintf("holiday calendar covers %s to %s, other payment dates skip weekends only",
+		from.Format(time.DateOnly), to.Format(time.DateOnly))
+}
+
+// daysIn returns the number of days in the month of t.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// accrual returns the share of the annual rate accrued between two payment
+// dates as a fraction num/den. 30/360 accrues a twelfth of the rate in every
+// period, actual/365 counts calendar days over 365 and actual/actual divides
+// the days of each year by the length of that year.
+func accrual(dayCount string, from, to time.Time) (num, den int64) {
+	switch dayCount {
+	case model.DayCountActual365:
+		return daysBetween(from, to), 365
+	case model.DayCountActualActual:
+		num, den = 0, 1
+		for from.Before(to) {
+			yearEnd := time.Date(from.Year()+1, 1, 1, 0, 0, 0, 0, from.Location())
+			end := yearEnd
+			if to.Before(end) {
+				end = to
+			}
+			days, length := daysBetween(from, end), daysBetween(yearEnd.AddDate(-1, 0, 0), yearEnd)
+			num, den = num*length+days*den, den*length
+			from = end
+		}
+		return num, den
+	default:
+		return 1, 12
+	}
+}
+
+// daysBetween returns the number of calendar days from one date to another.
+func daysBetween(from, to time.Time) int64 {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(end.Sub(start) / (24 * time.Hour))
+}
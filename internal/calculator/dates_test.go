@@ -0,0 +1,188 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/calendar"
+	"mortgage-calculator/internal/model"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalculator_CalculatePaymentDates(t *testing.T) {
+	calc := NewCalculator(WithClock(fixedClock(time.Date(2024, 1, 31, 15, 30, 0, 0, time.UTC))))
+
+	tests := []struct {
+		name       string
+		issueDate  *model.Date
+		paymentDay int
+		wantDates  []time.Time
+		wantLast   time.Time
+	}{
+		{
+			name:      "clock date with end-of-month clamping",
+			wantDates: []time.Time{date(2024, 2, 29), date(2024, 3, 31), date(2024, 4, 30)},
+			wantLast:  date(2044, 1, 31),
+		},
+		{
+			name:       "issue date and payment day",
+			issueDate:  &model.Date{Time: date(2023, 11, 20)},
+			paymentDay: 5,
+			wantDates:  []time.Time{date(2023, 12, 5), date(2024, 1, 5), date(2024, 2, 5)},
+			wantLast:   date(2043, 11, 5),
+		},
+		{
+			name:       "payment day clamped in february",
+			issueDate:  &model.Date{Time: date(2025, 1, 10)},
+			paymentDay: 30,
+			wantDates:  []time.Time{date(2025, 2, 28), date(2025, 3, 30), date(2025, 4, 30)},
+			wantLast:   date(2045, 1, 30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				IssueDate:      tt.issueDate,
+				PaymentDay:     tt.paymentDay,
+				Schedule:       true,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for i, want := range tt.wantDates {
+				if got := result.Schedule[i].Date; !got.Equal(want) {
+					t.Errorf("Payment %d: expected %s, got %s", i+1, want.Format(time.DateOnly), got.Format(time.DateOnly))
+				}
+			}
+			if got := result.Aggregates.LastPaymentDate; !got.Equal(tt.wantLast) {
+				t.Errorf("Expected last payment %s, got %s", tt.wantLast.Format(time.DateOnly), got.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculatePrepaymentByDate(t *testing.T) {
+	calc := NewCalculator()
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		IssueDate:      &model.Date{Time: date(2024, 1, 15)},
+		Prepayments: []model.Prepayment{
+			{Date: &model.Date{Time: date(2024, 6, 1)}, Amount: 100_000, Strategy: model.PrepaymentReduceTerm},
+		},
+		Schedule: true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The prepayment goes with the first payment on or after its date
+	if got := result.Schedule[4].Prepayment; got != 100_000 {
+		t.Errorf("Expected prepayment with the payment of 2024-06-15, got %f", got)
+	}
+}
+
+func TestCalculator_CalculateHolidayShift(t *testing.T) {
+	holidays := []time.Time{date(2025, 1, 1), date(2025, 1, 2), date(2025, 1, 3), date(2025, 1, 6), date(2025, 1, 7), date(2025, 1, 8)}
+	calc := NewCalculator(WithCalendar(calendar.New(holidays, []time.Time{date(2025, 3, 1)})))
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		IssueDate:      &model.Date{Time: date(2024, 12, 1)},
+		DayCount:       model.DayCountActual365,
+		Schedule:       true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Holidays, a weekend and a working Saturday
+	wantDates := []time.Time{date(2025, 1, 9), date(2025, 2, 3), date(2025, 3, 1)}
+	for i, want := range wantDates {
+		if got := result.Schedule[i].Date; !got.Equal(want) {
+			t.Errorf("Payment %d: expected %s, got %s", i+1, want.Format(time.DateOnly), got.Format(time.DateOnly))
+		}
+	}
+
+	// Interest accrues for the 39 days until the shifted payment
+	if got := result.Schedule[0].Interest; got != 34191.78 {
+		t.Errorf("Expected first interest 34191.78, got %.2f", got)
+	}
+}
+
+func TestCalculator_CalculateCalendarWarning(t *testing.T) {
+	calc := NewCalculator(WithCalendar(calendar.New([]time.Time{date(2025, 1, 1)}, nil)))
+
+	tests := []struct {
+		name        string
+		months      int
+		wantWarning string
+	}{
+		{"within the calendar", 11, ""},
+		{"beyond the calendar", 240, "holiday calendar covers 2025-01-01 to 2025-12-31, other payment dates skip weekends only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         tt.months,
+				Program:        model.MortgageProgram{Salary: true},
+				IssueDate:      &model.Date{Time: date(2025, 1, 15)},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := result.Aggregates.CalendarWarning; got != tt.wantWarning {
+				t.Errorf("Expected warning %q, got %q", tt.wantWarning, got)
+			}
+		})
+	}
+}
+
+func TestAccrual(t *testing.T) {
+	tests := []struct {
+		name     string
+		dayCount string
+		from, to time.Time
+		wantNum  int64
+		wantDen  int64
+	}{
+		{"30/360 by default", "", date(2025, 1, 15), date(2025, 2, 17), 1, 12},
+		{"actual/365", model.DayCountActual365, date(2025, 1, 15), date(2025, 2, 17), 33, 365},
+		{"actual/365 in a leap year", model.DayCountActual365, date(2024, 2, 15), date(2024, 3, 15), 29, 365},
+		{"actual/actual in a leap year", model.DayCountActualActual, date(2024, 2, 15), date(2024, 3, 15), 29, 366},
+		{"actual/actual across years", model.DayCountActualActual, date(2024, 12, 15), date(2025, 1, 15), 17*365 + 14*366, 366 * 365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, den := accrual(tt.dayCount, tt.from, tt.to)
+			if num*tt.wantDen != tt.wantNum*den {
+				t.Errorf("Expected %d/%d, got %d/%d", tt.wantNum, tt.wantDen, num, den)
+			}
+		})
+	}
+}
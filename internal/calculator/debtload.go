@@ -0,0 +1,42 @@
+package calculator
+
+import (
+	"math"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// DefaultDebtLoadLimits are the thresholds used when none are configured.
+func DefaultDebtLoadLimits() model.DebtLoadLimits {
+	return model.DebtLoadLimits{Warning: 50, Max: 80}
+}
+
+// debtLoad returns the debt-to-income ratio with the mortgage payment, the
+// ratio is rounded to hundredths of a percent before it is compared.
+func (c *calculatorImpl) debtLoad(income, debts, payment money.Money) *model.DebtLoad {
+	ratio := math.Round(float64(payment+debts)/float64(income)*100*100) / 100
+
+	level := model.DebtLoadNormal
+	switch {
+	case ratio > c.debtLoadLimits.Max:
+		level = model.DebtLoadExcessive
+	case ratio > c.debtLoadLimits.Warning:
+		level = model.DebtLoadHigh
+	}
+
+	return &model.DebtLoad{
+		MonthlyIncome:   income.Float(),
+		DebtPayments:    debts.Float(),
+		MortgagePayment: payment.Float(),
+		Ratio:           ratio,
+		Level:           level,
+		ExceedsLimit:    level == model.DebtLoadExcessive,
+	}
+}
+
+// paymentByIncome returns the largest mortgage payment that keeps the debt
+// load within the limit, zero when existing debts already exhaust it.
+func (c *calculatorImpl) paymentByIncome(income, debts money.Money) money.Money {
+	budget := income.Percent(c.debtLoadLimits.Max, 1, 1, money.HalfUp) - debts
+	return max(budget, 0)
+}
@@ -0,0 +1,113 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"testing"
+)
+
+func TestCalculator_CalculateDebtLoad(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name        string
+		income      float64
+		debts       float64
+		wantRatio   float64
+		wantLevel   string
+		wantExceeds bool
+	}{
+		{"normal", 100_000, 10_000, 43.46, model.DebtLoadNormal, false},
+		{"high", 60_000, 10_000, 72.43, model.DebtLoadHigh, false},
+		{"excessive", 50_000, 10_000, 86.92, model.DebtLoadExcessive, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				MonthlyIncome:  tt.income,
+				DebtPayments:   tt.debts,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			debtLoad := result.DebtLoad
+			if debtLoad == nil {
+				t.Fatal("Expected debt load")
+			}
+			if debtLoad.MortgagePayment != result.Aggregates.MonthlyPayment {
+				t.Errorf("Expected mortgage payment %f, got %f", result.Aggregates.MonthlyPayment, debtLoad.MortgagePayment)
+			}
+			if debtLoad.Ratio != tt.wantRatio {
+				t.Errorf("Expected ratio %.2f, got %.2f", tt.wantRatio, debtLoad.Ratio)
+			}
+			if debtLoad.Level != tt.wantLevel {
+				t.Errorf("Expected level %s, got %s", tt.wantLevel, debtLoad.Level)
+			}
+			if debtLoad.ExceedsLimit != tt.wantExceeds {
+				t.Errorf("Expected exceeds limit %v, got %v", tt.wantExceeds, debtLoad.ExceedsLimit)
+			}
+		})
+	}
+}
+
+func TestCalculator_AffordabilityByIncome(t *testing.T) {
+	calc := NewCalculator(WithDebtLoadLimits(model.DebtLoadLimits{Warning: 50, Max: 70}))
+
+	tests := []struct {
+		name          string
+		maxPayment    float64
+		debts         float64
+		wantPayment   float64
+		wantLimitedBy string
+	}{
+		{"income only", 0, 10_000, 60_000, model.LimitedByDebtLoad},
+		{"budget below the income limit", 50_000, 10_000, 50_000, model.LimitedByPayment},
+		{"debts lower the income limit", 50_000, 30_000, 40_000, model.LimitedByDebtLoad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Affordability(&model.AffordabilityRequest{
+				MaxMonthlyPayment: tt.maxPayment,
+				MonthlyIncome:     100_000,
+				DebtPayments:      tt.debts,
+				DownPayment:       5_000_000,
+				Months:            240,
+				Program:           model.MortgageProgram{Base: true},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.LimitedBy != tt.wantLimitedBy {
+				t.Errorf("Expected limited by %s, got %s", tt.wantLimitedBy, result.LimitedBy)
+			}
+			if result.MonthlyPayment > tt.wantPayment || result.MonthlyPayment < tt.wantPayment-1 {
+				t.Errorf("Expected monthly payment close to %.0f, got %.2f", tt.wantPayment, result.MonthlyPayment)
+			}
+			if result.DebtLoad == nil || result.DebtLoad.ExceedsLimit {
+				t.Errorf("Expected debt load within the limit, got %+v", result.DebtLoad)
+			}
+		})
+	}
+}
+
+func TestCalculator_AffordabilityDebtsExhaustIncome(t *testing.T) {
+	calc := NewCalculator()
+
+	_, err := calc.Affordability(&model.AffordabilityRequest{
+		MonthlyIncome: 100_000,
+		DebtPayments:  80_000,
+		DownPayment:   1_000_000,
+		Months:        240,
+		Program:       model.MortgageProgram{Base: true},
+	})
+	if err != ErrNotAffordable {
+		t.Errorf("Expected error %v, got %v", ErrNotAffordable, err)
+	}
+}
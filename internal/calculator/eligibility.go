@@ -0,0 +1,63 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"slices"
+)
+
+// CheckEligibility applies the eligibility rules of every program of the
+// calculator catalog to the borrower.
+func CheckEligibility(calc Calculator, profile *model.BorrowerProfile) *model.Eligibility {
+	eligibility := &model.Eligibility{
+		Profile:  *profile,
+		Eligible: []model.Program{},
+	}
+
+	for _, program := range calc.Programs() {
+		reasons := ineligibilityReasons(program.Eligibility, profile)
+		if len(reasons) == 0 {
+			eligibility.Eligible = append(eligibility.Eligible, program)
+			continue
+		}
+
+		eligibility.Rejected = append(eligibility.Rejected, model.IneligibleProgram{
+			ProgramID:   program.ID,
+			ProgramName: program.Name,
+			Reasons:     reasons,
+		})
+	}
+
+	return eligibility
+}
+
+// ineligibilityReasons returns every rule the borrower fails, none for a
+// program without rules.
+func ineligibilityReasons(rules *model.EligibilityRules, profile *model.BorrowerProfile) []string {
+	if rules == nil {
+		return nil
+	}
+
+	var reasons []string
+	if rules.MinAge > 0 && profile.Age < rules.MinAge {
+		reasons = append(reasons, model.ReasonAgeBelowMinimum)
+	}
+	if rules.MaxAge > 0 && profile.Age > rules.MaxAge {
+		reasons = append(reasons, model.ReasonAgeAboveMaximum)
+	}
+	if profile.Children < rules.MinChildren {
+		reasons = append(reasons, model.ReasonNotEnoughChildren)
+	}
+	if rules.MilitaryService && !profile.MilitaryService {
+		reasons = append(reasons, model.ReasonMilitaryServiceRequired)
+	}
+	if rules.SalaryProject && !profile.SalaryProject {
+		reasons = append(reasons, model.ReasonSalaryProjectRequired)
+	}
+	if len(rules.Regions) > 0 && !slices.Contains(rules.Regions, profile.Region) {
+		reasons = append(reasons, model.ReasonRegionNotEligible)
+	}
+	if len(rules.PropertyTypes) > 0 && !slices.Contains(rules.PropertyTypes, profile.PropertyType) {
+		reasons = append(reasons, model.ReasonPropertyTypeNotEligible)
+	}
+	return reasons
+}
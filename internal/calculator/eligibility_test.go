@@ -0,0 +1,121 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestIneligibilityReasons(t *testing.T) {
+	rules := &model.EligibilityRules{
+		MinAge:        21,
+		MaxAge:        65,
+		MinChildren:   1,
+		SalaryProject: true,
+		Regions:       []string{"77", "50"},
+		PropertyTypes: []string{model.PropertyNewBuilding},
+	}
+	eligible := model.BorrowerProfile{Age: 35, Children: 2, SalaryProject: true, Region: "77", PropertyType: model.PropertyNewBuilding}
+
+	tests := []struct {
+		name    string
+		rules   *model.EligibilityRules
+		profile func(p *model.BorrowerProfile)
+		want    []string
+	}{
+		{"no rules", nil, func(p *model.BorrowerProfile) { p.Age = 90 }, nil},
+		{"eligible", rules, func(p *model.BorrowerProfile) {}, nil},
+		{"too young", rules, func(p *model.BorrowerProfile) { p.Age = 20 }, []string{model.ReasonAgeBelowMinimum}},
+		{"too old", rules, func(p *model.BorrowerProfile) { p.Age = 66 }, []string{model.ReasonAgeAboveMaximum}},
+		{
+			name:  "several failed rules",
+			rules: rules,
+			profile: func(p *model.BorrowerProfile) {
+				p.Children = 0
+				p.SalaryProject = false
+				p.Region = "66"
+				p.PropertyType = model.PropertySecondary
+			},
+			want: []string{
+				model.ReasonNotEnoughChildren,
+				model.ReasonSalaryProjectRequired,
+				model.ReasonRegionNotEligible,
+				model.ReasonPropertyTypeNotEligible,
+			},
+		},
+		{
+			name:    "military service",
+			rules:   &model.EligibilityRules{MilitaryService: true},
+			profile: func(p *model.BorrowerProfile) {},
+			want:    []string{model.ReasonMilitaryServiceRequired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := eligible
+			tt.profile(&profile)
+
+			if got := ineligibilityReasons(tt.rules, &profile); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected reasons %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateBorrowerEligibility(t *testing.T) {
+	calc := NewCalculator()
+	request := model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Military: true},
+		Borrower:       &model.BorrowerProfile{Age: 30, Region: "77", PropertyType: model.PropertySecondary},
+	}
+
+	_, err := calc.Calculate(&request)
+	if !errors.Is(err, ErrNotEligible) {
+		t.Errorf("Expected error %v, got %v", ErrNotEligible, err)
+	}
+	var eligibilityErr *EligibilityError
+	if !errors.As(err, &eligibilityErr) || !reflect.DeepEqual(eligibilityErr.Reasons, []string{model.ReasonMilitaryServiceRequired}) {
+		t.Errorf("Expected reason %s, got %v", model.ReasonMilitaryServiceRequired, err)
+	}
+
+	request.Borrower.MilitaryService = true
+	result, err := calc.Calculate(&request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.EligibilityUnverified {
+		t.Error("Expected eligibility verified with the borrower")
+	}
+
+	// Without a profile the program is calculated but flagged
+	request.Borrower = nil
+	result, err = calc.Calculate(&request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.EligibilityUnverified {
+		t.Error("Expected eligibility unverified without the borrower")
+	}
+}
+
+func TestCheckEligibility(t *testing.T) {
+	result := CheckEligibility(NewCalculator(), &model.BorrowerProfile{
+		Age: 30, SalaryProject: true, Region: "77", PropertyType: model.PropertySecondary,
+	})
+
+	var eligible []string
+	for _, program := range result.Eligible {
+		eligible = append(eligible, program.ID)
+	}
+	if want := []string{model.ProgramSalary, model.ProgramBase}; !reflect.DeepEqual(eligible, want) {
+		t.Errorf("Expected eligible programs %v, got %v", want, eligible)
+	}
+	if len(result.Rejected) != 1 || result.Rejected[0].ProgramID != model.ProgramMilitary {
+		t.Errorf("Expected military program rejected, got %+v", result.Rejected)
+	}
+}
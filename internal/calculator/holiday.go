@@ -0,0 +1,74 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// applyHoliday suspends or reduces the payments of the holiday window. The
+// paid amount covers interest first, the deferred parts of every holiday
+// payment are repaid one by one after the last payment of the schedule. No
+// interest accrues on the deferred amounts, so the overpayment is unchanged
+// and the term is extended by the number of deferred payments.
+func applyHoliday(schedule []loanPeriod, holiday *model.PaymentHoliday, dates paymentDates) ([]loanPeriod, *model.HolidayResult, error) {
+	end := holiday.FromMonth + holiday.Months - 1
+	if end >= len(schedule) {
+		return nil, nil, ErrHolidayOutOfTerm
+	}
+
+	var reduced money.Money
+	if holiday.Mode == model.HolidayReduce {
+		reduced = money.FromFloat(holiday.Payment)
+	}
+
+	result := make([]loanPeriod, 0, len(schedule)+holiday.Months)
+	var deferred []loanPeriod
+	var deferredInterest, deferredPrincipal money.Money
+	for _, p := range schedule {
+		if p.number >= holiday.FromMonth && p.number <= end {
+			paid := money.Min(reduced, p.payment)
+			interest := money.Min(paid, p.interest)
+			principal := paid - interest
+
+			if paid < p.payment {
+				deferred = append(deferred, loanPeriod{
+					rate:      p.rate,
+					payment:   p.payment - paid,
+					interest:  p.interest - interest,
+					principal: p.principal - principal,
+				})
+			}
+			deferredInterest += p.interest - interest
+			deferredPrincipal += p.principal - principal
+
+			p.payment, p.interest, p.principal = paid, interest, principal
+		}
+
+		p.balance += deferredPrincipal
+		result = append(result, p)
+	}
+
+	// Deferred payments follow the last payment of the schedule
+	balance := deferredPrincipal
+	last := schedule[len(schedule)-1].number
+	for i, d := range deferred {
+		balance -= d.principal
+		d.number = last + i + 1
+		d.date = dates.date(d.number)
+		d.balance = balance
+		result = append(result, d)
+	}
+
+	newLast := result[len(result)-1]
+	return result, &model.HolidayResult{
+		FromMonth:               holiday.FromMonth,
+		Months:                  holiday.Months,
+		Mode:                    holiday.Mode,
+		DeferredInterest:        deferredInterest.Float(),
+		DeferredPrincipal:       deferredPrincipal.Float(),
+		TermExtension:           len(deferred),
+		Overpayment:             totalInterest(result).Float(),
+		BaselineLastPaymentDate: schedule[len(schedule)-1].date,
+		LastPaymentDate:         newLast.date,
+	}, nil
+}
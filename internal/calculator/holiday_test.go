@@ -0,0 +1,94 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculatePaymentHoliday(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name        string
+		holiday     model.PaymentHoliday
+		wantPayment float64
+	}{
+		{
+			name:        "suspended payments",
+			holiday:     model.PaymentHoliday{FromMonth: 13, Months: 6, Mode: model.HolidaySuspend},
+			wantPayment: 0,
+		},
+		{
+			name:        "reduced payments below the interest",
+			holiday:     model.PaymentHoliday{FromMonth: 13, Months: 6, Mode: model.HolidayReduce, Payment: 20_000},
+			wantPayment: 20_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				IssueDate:      &model.Date{Time: date(2024, 1, 15)},
+				Holiday:        &tt.holiday,
+				Schedule:       true,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			holiday := result.Holiday
+			if holiday == nil {
+				t.Fatal("Expected holiday result")
+			}
+			if holiday.TermExtension != 6 || len(result.Schedule) != 246 {
+				t.Errorf("Expected 6 more payments, got extension %d and %d payments", holiday.TermExtension, len(result.Schedule))
+			}
+			if !holiday.BaselineLastPaymentDate.Equal(result.Aggregates.LastPaymentDate) {
+				t.Errorf("Expected baseline end %s, got %s", result.Aggregates.LastPaymentDate, holiday.BaselineLastPaymentDate)
+			}
+			if want := date(2044, 7, 15); !holiday.LastPaymentDate.Equal(want) {
+				t.Errorf("Expected new end %s, got %s", want, holiday.LastPaymentDate)
+			}
+
+			// Deferred amounts bear no interest
+			if holiday.Overpayment != result.Aggregates.Overpayment {
+				t.Errorf("Expected overpayment %f, got %f", result.Aggregates.Overpayment, holiday.Overpayment)
+			}
+
+			var deferred money.Money
+			for _, p := range result.Schedule[12:18] {
+				if p.Payment != tt.wantPayment {
+					t.Errorf("Payment %d: expected %.2f, got %.2f", p.Number, tt.wantPayment, p.Payment)
+				}
+			}
+			for _, p := range result.Schedule[240:] {
+				deferred += money.FromFloat(p.Payment)
+			}
+			if want := money.FromFloat(holiday.DeferredInterest) + money.FromFloat(holiday.DeferredPrincipal); deferred != want {
+				t.Errorf("Expected deferred payments of %s, got %s", want, deferred)
+			}
+			if got := result.Schedule[len(result.Schedule)-1].Balance; got != 0 {
+				t.Errorf("Expected the loan to be repaid, got balance %.2f", got)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculatePaymentHolidayOutOfTerm(t *testing.T) {
+	_, err := NewCalculator().Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         12,
+		Program:        model.MortgageProgram{Salary: true},
+		Holiday:        &model.PaymentHoliday{FromMonth: 10, Months: 3, Mode: model.HolidaySuspend},
+	})
+	if !errors.Is(err, ErrHolidayOutOfTerm) {
+		t.Errorf("Expected error %v, got %v", ErrHolidayOutOfTerm, err)
+	}
+}
@@ -0,0 +1,38 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// insurancePremiums returns premiums by insurance year. The premium for year
+// k is charged on the debt left after period 12k and only while the loan is
+// still outstanding, the first year is charged on the loan sum.
+func insurancePremiums(loanSum money.Money, schedule []loanPeriod, insurance *model.Insurance, mode money.Mode) []money.Money {
+	if insurance == nil {
+		return nil
+	}
+
+	tariff := insurance.PropertyRate + insurance.TitleRate
+	if !insurance.RefuseLife {
+		tariff += insurance.LifeRate
+	}
+	if tariff == 0 {
+		return nil
+	}
+
+	premiums := []money.Money{loanSum.Percent(tariff, 1, 1, mode)}
+	for period := 12; period < len(schedule); period += 12 {
+		balance := schedule[period-1].balance
+		premiums = append(premiums, balance.Percent(tariff, 1, 1, mode))
+	}
+	return premiums
+}
+
+// lifeInsuranceSurcharge returns the rate increase for refusing life insurance.
+func lifeInsuranceSurcharge(program model.Program, insurance *model.Insurance) float64 {
+	if insurance == nil || !insurance.RefuseLife {
+		return 0
+	}
+	return program.LifeInsuranceSurcharge
+}
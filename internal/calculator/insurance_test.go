@@ -0,0 +1,69 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculateInsurance(t *testing.T) {
+	tests := []struct {
+		name             string
+		insurance        *model.Insurance
+		wantRate         float64
+		wantFirstPremium float64
+	}{
+		{
+			name:             "property, life and title",
+			insurance:        &model.Insurance{PropertyRate: 0.1, LifeRate: 0.2, TitleRate: 0.1},
+			wantRate:         8,
+			wantFirstPremium: 16_000,
+		},
+		{
+			name:             "life insurance refused",
+			insurance:        &model.Insurance{PropertyRate: 0.1, LifeRate: 0.2, TitleRate: 0.1, RefuseLife: true},
+			wantRate:         9,
+			wantFirstPremium: 8_000,
+		},
+	}
+
+	calc := NewCalculator()
+	request := &model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Salary: true},
+		Schedule:       true,
+	}
+	uninsured, _ := calc.Calculate(request)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request.Insurance = tt.insurance
+			result, err := calc.Calculate(request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			aggregates := result.Aggregates
+			if aggregates.Rate != tt.wantRate {
+				t.Errorf("Expected rate %f, got %f", tt.wantRate, aggregates.Rate)
+			}
+
+			// Premiums follow the amortizing balance, so the total is below
+			// the first premium paid for every year of the term
+			if aggregates.InsuranceCost <= tt.wantFirstPremium || aggregates.InsuranceCost >= tt.wantFirstPremium*20 {
+				t.Errorf("Unexpected insurance cost %f for first premium %f", aggregates.InsuranceCost, tt.wantFirstPremium)
+			}
+
+			premiums := insurancePremiums(money.FromFloat(aggregates.LoanSum), nil, tt.insurance, money.HalfUp)
+			if premiums[0].Float() != tt.wantFirstPremium {
+				t.Errorf("Expected first premium %f, got %f", tt.wantFirstPremium, premiums[0].Float())
+			}
+
+			if aggregates.FullCostRate <= uninsured.Aggregates.FullCostRate {
+				t.Errorf("Expected insurance to raise the full cost rate above %f, got %f", uninsured.Aggregates.FullCostRate, aggregates.FullCostRate)
+			}
+		})
+	}
+}
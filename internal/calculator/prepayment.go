@@ -0,0 +1,57 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// schedulePrepayments expands one-off and recurring prepayments into
+// per-period amounts. Dates are mapped to the first payment on or after them.
+func schedulePrepayments(prepayments []model.Prepayment, months int, dates paymentDates) (map[int]scheduledPrepayment, error) {
+	scheduled := make(map[int]scheduledPrepayment)
+
+	for _, prepayment := range prepayments {
+		month, err := prepaymentMonth(prepayment, months, dates)
+		if err != nil {
+			return nil, err
+		}
+
+		for count := 0; month <= months; count++ {
+			if prepayment.Count > 0 && count == prepayment.Count {
+				break
+			}
+
+			s := scheduled[month]
+			s.amount += money.FromFloat(prepayment.Amount)
+			s.reducePayment = s.reducePayment || prepayment.Strategy == model.PrepaymentReducePayment
+			scheduled[month] = s
+
+			if prepayment.EveryMonths == 0 {
+				break
+			}
+			month += prepayment.EveryMonths
+		}
+	}
+
+	return scheduled, nil
+}
+
+// prepaymentMonth returns the period number of the first prepayment.
+func prepaymentMonth(prepayment model.Prepayment, months int, dates paymentDates) (int, error) {
+	switch {
+	case prepayment.Month > 0:
+		if prepayment.Month > months {
+			return 0, ErrPrepaymentOutOfTerm
+		}
+		return prepayment.Month, nil
+	case prepayment.Date != nil:
+		for i := 1; i <= months; i++ {
+			if !dates.date(i).Before(prepayment.Date.Time) {
+				return i, nil
+			}
+		}
+		return 0, ErrPrepaymentOutOfTerm
+	default:
+		return 0, ErrPrepaymentWithoutDate
+	}
+}
@@ -0,0 +1,94 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/keyrate"
+	"mortgage-calculator/internal/model"
+	"sort"
+	"time"
+)
+
+// rateFunc returns the annual rate for the period starting at periodStart.
+type rateFunc func(period int, periodStart time.Time) float64
+
+// fixedRate is a rate that never changes.
+func fixedRate(rate float64) rateFunc {
+	return func(int, time.Time) float64 {
+		return rate
+	}
+}
+
+// rateTimeline returns the rate function for the program rate followed by
+// the changes. Surcharge is added to every rate of the timeline.
+func (c *calculatorImpl) rateTimeline(baseRate, surcharge float64, changes []model.RateChange, months int, issue time.Time) (rateFunc, error) {
+	if len(changes) == 0 {
+		return fixedRate(baseRate + surcharge), nil
+	}
+
+	for _, change := range changes {
+		if (change.Rate == nil) == (change.Spread == nil) {
+			return nil, ErrInvalidRateChange
+		}
+		if change.FromMonth > months {
+			return nil, ErrRateChangeOutOfTerm
+		}
+		if change.Spread == nil {
+			continue
+		}
+		// Periods start on or after the issue date, so a known key rate
+		// on the issue date means it is known for every period
+		if c.keyRates == nil {
+			return nil, ErrKeyRateUnavailable
+		}
+		if _, ok := c.keyRates.RateOn(issue); !ok {
+			return nil, ErrKeyRateUnavailable
+		}
+	}
+
+	timeline := make([]model.RateChange, len(changes))
+	copy(timeline, changes)
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].FromMonth < timeline[j].FromMonth
+	})
+
+	keyRates := c.keyRates
+	rates := func(period int, periodStart time.Time) float64 {
+		rate := baseRate
+		for _, change := range timeline {
+			if change.FromMonth > period {
+				break
+			}
+			rate = floatingRate(change, keyRates, periodStart)
+		}
+		return rate + surcharge
+	}
+
+	return rates, nil
+}
+
+// floatingRate returns the rate set by the change for the period start. A
+// negative spread never takes the floating rate below zero.
+func floatingRate(change model.RateChange, keyRates *keyrate.History, periodStart time.Time) float64 {
+	if change.Rate != nil {
+		return *change.Rate
+	}
+	keyRate, _ := keyRates.RateOn(periodStart)
+	return max(keyRate+*change.Spread, 0)
+}
+
+// rateSegments groups consecutive periods with the same rate.
+func rateSegments(schedule []loanPeriod) []model.RateSegment {
+	var segments []model.RateSegment
+	for i, p := range schedule {
+		if i > 0 && p.rate == schedule[i-1].rate {
+			segments[len(segments)-1].ToMonth = p.number
+			continue
+		}
+		segments = append(segments, model.RateSegment{
+			FromMonth: p.number,
+			ToMonth:   p.number,
+			Rate:      p.rate,
+			Payment:   p.payment.Float(),
+		})
+	}
+	return segments
+}
@@ -0,0 +1,95 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/keyrate"
+	"mortgage-calculator/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCalculator_CalculateRateTimeline(t *testing.T) {
+	rate := func(v float64) *float64 { return &v }
+	history := keyrate.NewHistory(map[time.Time]float64{
+		time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC): 16,
+	})
+
+	tests := []struct {
+		name         string
+		calc         Calculator
+		timeline     []model.RateChange
+		wantSegments []model.RateSegment
+		wantError    error
+	}{
+		{
+			name:     "fixed rate then higher fixed rate",
+			calc:     NewCalculator(),
+			timeline: []model.RateChange{{FromMonth: 37, Rate: rate(12)}},
+			wantSegments: []model.RateSegment{
+				{FromMonth: 1, ToMonth: 36, Rate: 8, Payment: 33458},
+				{FromMonth: 37, ToMonth: 240, Rate: 12, Payment: 42879},
+			},
+		},
+		{
+			name:     "fixed rate then key rate plus spread",
+			calc:     NewCalculator(WithKeyRates(history)),
+			timeline: []model.RateChange{{FromMonth: 13, Spread: rate(2)}},
+			wantSegments: []model.RateSegment{
+				{FromMonth: 1, ToMonth: 12, Rate: 8, Payment: 33458},
+				{FromMonth: 13, ToMonth: 240, Rate: 18, Payment: 60771},
+			},
+		},
+		{
+			name:     "key rate plus negative spread is floored at zero",
+			calc:     NewCalculator(WithKeyRates(history)),
+			timeline: []model.RateChange{{FromMonth: 13, Spread: rate(-20)}},
+			wantSegments: []model.RateSegment{
+				{FromMonth: 1, ToMonth: 12, Rate: 8, Payment: 33458},
+				{FromMonth: 13, ToMonth: 240, Rate: 0, Payment: 17173},
+			},
+		},
+		{
+			name:      "floating rate without key rate history",
+			calc:      NewCalculator(),
+			timeline:  []model.RateChange{{FromMonth: 13, Spread: rate(2)}},
+			wantError: ErrKeyRateUnavailable,
+		},
+		{
+			name:      "rate and spread together",
+			calc:      NewCalculator(WithKeyRates(history)),
+			timeline:  []model.RateChange{{FromMonth: 13, Rate: rate(10), Spread: rate(2)}},
+			wantError: ErrInvalidRateChange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				RateTimeline:   tt.timeline,
+			})
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.RateSegments) != len(tt.wantSegments) {
+				t.Fatalf("Expected segments %+v, got %+v", tt.wantSegments, result.RateSegments)
+			}
+			for i, want := range tt.wantSegments {
+				if result.RateSegments[i] != want {
+					t.Errorf("Expected segment %+v, got %+v", want, result.RateSegments[i])
+				}
+			}
+		})
+	}
+}
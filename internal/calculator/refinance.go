@@ -0,0 +1,123 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+func (c *calculatorImpl) Refinance(req *model.RefinanceRequest) (*model.RefinanceResult, error) {
+	program, err := c.program(req.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	months := req.Months
+	if months == 0 {
+		months = req.RemainingMonths
+	}
+
+	// The new loan repays the current balance, costs are paid separately
+	balance := money.FromFloat(req.Balance)
+	if program.MaxMonths > 0 && months > program.MaxMonths {
+		return nil, ErrTermTooLong
+	}
+	if program.MaxLoan > 0 && balance > money.FromFloat(program.MaxLoan) {
+		return nil, ErrLoanTooLarge
+	}
+
+	// The rate grid prices the new loan by its share of the property value
+	rate := program.Rate
+	var tier *model.RateTier
+	if program.RateGrid != nil {
+		if req.ObjectCost == 0 {
+			return nil, ErrObjectCostRequired
+		}
+		if rate, tier, err = c.programRate(program, balance, money.FromFloat(req.ObjectCost), months); err != nil {
+			return nil, err
+		}
+	}
+
+	costs := money.FromFloat(req.EarlyRepaymentFee)
+	for _, fee := range req.Costs {
+		costs += money.FromFloat(fee.Amount)
+	}
+
+	dates := c.loanDates(req.IssueDate, 0)
+	current := buildSchedule(scheduleParams{
+		loanSum:       balance,
+		months:        req.RemainingMonths,
+		repaymentType: model.RepaymentAnnuity,
+		rates:         fixedRate(req.Rate),
+		rounding:      c.rounding,
+		dates:         dates,
+	})
+	offerParams := scheduleParams{
+		loanSum:       balance,
+		months:        months,
+		repaymentType: model.RepaymentAnnuity,
+		rates:         fixedRate(rate),
+		rounding:      c.rounding,
+		dates:         dates,
+	}
+
+	// A balance above the subsidy limit is refinanced with two tranches
+	var tranches []tranche
+	var offer []loanPeriod
+	if limit := money.FromFloat(program.SubsidyLimit); limit > 0 && balance > limit {
+		tranches = splitLoan(offerParams, limit, program.ExcessRate)
+		offer = combinedSchedule(tranches)
+	} else {
+		offer = buildSchedule(offerParams)
+	}
+
+	currentInterest, offerInterest := totalInterest(current), totalInterest(offer)
+	result := &model.RefinanceResult{
+		Program:        selectedProgram(req.Program, program.ID),
+		Current:        refinanceLoan(current, req.Rate),
+		Offer:          refinanceLoan(offer, rate),
+		RateTier:       tier,
+		MonthlySavings: (current[0].payment - offer[0].payment).Float(),
+		InterestSaved:  (currentInterest - offerInterest).Float(),
+		Costs:          costs.Float(),
+		NetSavings:     (currentInterest - offerInterest - costs).Float(),
+		BreakEvenMonth: breakEvenMonth(current, offer, costs),
+	}
+
+	if tranches != nil {
+		result.Offer.Tranches = toModelTranches(tranches, 0)
+	}
+
+	return result, nil
+}
+
+// breakEvenMonth returns the first period when the accumulated difference
+// of payments covers the costs, zero when it never does. A loan that is
+// already repaid pays nothing in the remaining periods of the other one.
+func breakEvenMonth(current, offer []loanPeriod, costs money.Money) int {
+	var saved money.Money
+	for i := 0; i < max(len(current), len(offer)); i++ {
+		if i < len(current) {
+			saved += current[i].payment
+		}
+		if i < len(offer) {
+			saved -= offer[i].payment
+		}
+		if saved >= costs {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// refinanceLoan summarizes a schedule of the refinancing comparison.
+func refinanceLoan(schedule []loanPeriod, rate float64) model.RefinanceLoan {
+	last := schedule[len(schedule)-1]
+	return model.RefinanceLoan{
+		Rate:            rate,
+		Months:          last.number,
+		MonthlyPayment:  schedule[0].payment.Float(),
+		Overpayment:     totalInterest(schedule).Float(),
+		LastPaymentDate: last.date,
+		Schedule:        toModelSchedule(schedule),
+	}
+}
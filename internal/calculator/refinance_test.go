@@ -0,0 +1,77 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_Refinance(t *testing.T) {
+	calc := NewCalculator(WithClock(fixedClock(date(2025, 3, 10))))
+
+	result, err := calc.Refinance(&model.RefinanceRequest{
+		Balance:           3_000_000,
+		Rate:              16,
+		RemainingMonths:   120,
+		EarlyRepaymentFee: 30_000,
+		Costs:             []model.Fee{{Name: "appraisal", Amount: 5_000}},
+		Program:           model.MortgageProgram{ID: model.ProgramBase},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := result.Current.MonthlyPayment; got != 50254 {
+		t.Errorf("Expected current payment 50254, got %f", got)
+	}
+	if got := result.Offer.MonthlyPayment; got != 39645 {
+		t.Errorf("Expected offer payment 39645, got %f", got)
+	}
+	if got := result.MonthlySavings; got != 10609 {
+		t.Errorf("Expected monthly savings 10609, got %f", got)
+	}
+	if got := result.Costs; got != 35_000 {
+		t.Errorf("Expected costs 35000, got %f", got)
+	}
+	// 3 * 10609 covers the costs of 35000 only in the fourth month
+	if got := result.BreakEvenMonth; got != 4 {
+		t.Errorf("Expected break-even month 4, got %d", got)
+	}
+	if got, want := result.InterestSaved, result.Current.Overpayment-result.Offer.Overpayment; got != want {
+		t.Errorf("Expected interest saved %f, got %f", want, got)
+	}
+	if len(result.Current.Schedule) != 120 || len(result.Offer.Schedule) != 120 {
+		t.Errorf("Expected both schedules of 120 periods, got %d and %d", len(result.Current.Schedule), len(result.Offer.Schedule))
+	}
+}
+
+func TestBreakEvenMonth(t *testing.T) {
+	periods := func(payments ...money.Money) []loanPeriod {
+		schedule := make([]loanPeriod, len(payments))
+		for i, p := range payments {
+			schedule[i] = loanPeriod{number: i + 1, payment: p}
+		}
+		return schedule
+	}
+
+	tests := []struct {
+		name    string
+		current []loanPeriod
+		offer   []loanPeriod
+		costs   money.Money
+		want    int
+	}{
+		{"no costs", periods(100, 100), periods(90, 90), 0, 1},
+		{"costs recovered", periods(100, 100, 100), periods(90, 90, 90), 25, 3},
+		{"never recovered", periods(100, 100), periods(90, 90), 50, 0},
+		{"longer offer term eats the savings", periods(100, 100), periods(60, 60, 60, 60), 100, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := breakEvenMonth(tt.current, tt.offer, tt.costs); got != tt.want {
+				t.Errorf("Expected break-even month %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package calculator
+
+import (
+	"math"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"time"
+)
+
+// scheduleParams describes a loan for buildSchedule.
+type scheduleParams struct {
+	loanSum       money.Money
+	months        int
+	repaymentType string
+	// rates gives the annual rate of every period, the annuity payment is
+	// recalculated over the remaining term whenever the rate changes.
+	rates    rateFunc
+	rounding money.Rounding
+	dates    paymentDates
+	// dayCount is the interest accrual convention, see accrual.
+	dayCount string
+	// prepayments are early repayments keyed by period number.
+	prepayments map[int]scheduledPrepayment
+	// graceMonths are paid with gracePayment, zero means interest only.
+	graceMonths  int
+	gracePayment money.Money
+	// disbursements are construction tranches released with the payment of
+	// the period they are keyed by, the rest of loanSum is released at issue.
+	disbursements map[int]money.Money
+}
+
+// scheduledPrepayment is the total early repayment for a single period.
+type scheduledPrepayment struct {
+	amount        money.Money
+	reducePayment bool
+}
+
+// loanPeriod is a schedule row in exact money, see model.SchedulePeriod.
+type loanPeriod struct {
+	number     int
+	date       time.Time
+	rate       float64
+	payment    money.Money
+	interest   money.Money
+	principal  money.Money
+	prepayment money.Money
+	// disbursement is the tranche released after the payment
+	disbursement money.Money
+	balance      money.Money
+}
+
+// buildSchedule splits every payment into interest and principal.
+// Interest is rounded to kopecks and the last payment closes the remaining
+// balance, so principal and prepayment parts always sum up to loanSum.
+// The loan is disbursed at issue unless construction tranches are given.
+func buildSchedule(p scheduleParams) []loanPeriod {
+	schedule := make([]loanPeriod, 0, p.months)
+	mode := p.rounding.Mode
+	balance := p.loanSum
+	lastDisbursement := 0
+	for period, amount := range p.disbursements {
+		balance -= amount
+		lastDisbursement = max(lastDisbursement, period)
+	}
+	rate := p.rates(1, p.dates.date(0))
+	payment := annuityPayment(balance, rate, p.months, p.rounding)
+	fixedPrincipal := p.loanSum.Div(int64(p.months), mode)
+
+	for i := 1; i <= p.months; i++ {
+		// Rate reset, the annuity is recalculated for the remaining term
+		if r := p.rates(i, p.dates.date(i-1)); r != rate {
+			rate = r
+			payment = annuityPayment(balance, rate, p.months-i+1, p.rounding)
+		}
+
+		// Grace end, the loan is amortized over the remaining term
+		if p.graceMonths > 0 && i == p.graceMonths+1 {
+			remaining := p.months - p.graceMonths
+			payment = annuityPayment(balance, rate, remaining, p.rounding)
+			fixedPrincipal = balance.Div(int64(remaining), mode)
+		}
+
+		num, den := accrual(p.dayCount, p.dates.date(i-1), p.dates.date(i))
+		interest := balance.Percent(rate, num, den, mode)
+
+		var principal money.Money
+		switch {
+		case i <= p.graceMonths:
+			principal = max(p.gracePayment-interest, 0)
+		case p.repaymentType == model.RepaymentDifferentiated:
+			principal = fixedPrincipal
+		default:
+			principal = payment - interest
+		}
+		if i == p.months || principal > balance {
+			principal = balance
+		}
+		balance -= principal
+
+		// Early repayment goes after the regular payment
+		var prepaid money.Money
+		if prepayment, ok := p.prepayments[i]; ok && balance > 0 {
+			prepaid = money.Min(prepayment.amount, balance)
+			balance -= prepaid
+
+			if prepayment.reducePayment && balance > 0 {
+				remaining := p.months - i
+				payment = annuityPayment(balance, rate, remaining, p.rounding)
+				fixedPrincipal = balance.Div(int64(remaining), mode)
+			}
+		}
+
+		// Construction tranches accrue interest from the next period
+		disbursed := p.disbursements[i]
+		balance += disbursed
+
+		schedule = append(schedule, loanPeriod{
+			number:       i,
+			date:         p.dates.date(i),
+			rate:         rate,
+			payment:      interest + principal,
+			interest:     interest,
+			principal:    principal,
+			prepayment:   prepaid,
+			disbursement: disbursed,
+			balance:      balance,
+		})
+
+		if balance == 0 && i >= lastDisbursement {
+			break
+		}
+	}
+
+	return schedule
+}
+
+// annuityPayment returns the fixed monthly payment rounded by the policy.
+// Zero-rate loans are repaid in equal parts, for small rates the annuity
+// coefficient is computed via Expm1/Log1p to avoid cancellation errors.
+func annuityPayment(loanSum money.Money, annualRate float64, months int, rounding money.Rounding) money.Money {
+	monthlyRate := annualRate / 12 / 100
+	if monthlyRate == 0 {
+		return rounding.Payment(loanSum.Float() / float64(months))
+	}
+
+	annuityCoeff := monthlyRate / -math.Expm1(-float64(months)*math.Log1p(monthlyRate))
+	return rounding.Payment(loanSum.Float() * annuityCoeff)
+}
+
+// presentValue returns the loan repaid by the given annuity payment.
+func presentValue(payment money.Money, annualRate float64, months int) money.Money {
+	monthlyRate := annualRate / 12 / 100
+	if monthlyRate == 0 {
+		return payment * money.Money(months)
+	}
+
+	discount := -math.Expm1(-float64(months)*math.Log1p(monthlyRate)) / monthlyRate
+	return money.FromFloat(payment.Float() * discount)
+}
+
+// totalInterest returns the sum of interest parts of the schedule.
+func totalInterest(schedule []loanPeriod) money.Money {
+	var total money.Money
+	for _, p := range schedule {
+		total += p.interest
+	}
+	return total
+}
+
+// totalPrepaid returns the sum of early repayments of the schedule.
+func totalPrepaid(schedule []loanPeriod) money.Money {
+	var total money.Money
+	for _, p := range schedule {
+		total += p.prepayment
+	}
+	return total
+}
+
+// toModelSchedule converts the schedule to the response representation.
+func toModelSchedule(schedule []loanPeriod) []model.SchedulePeriod {
+	periods := make([]model.SchedulePeriod, 0, len(schedule))
+	for _, p := range schedule {
+		periods = append(periods, model.SchedulePeriod{
+			Number:       p.number,
+			Date:         p.date,
+			Payment:      p.payment.Float(),
+			Interest:     p.interest.Float(),
+			Principal:    p.principal.Float(),
+			Prepayment:   p.prepayment.Float(),
+			Disbursement: p.disbursement.Float(),
+			Balance:      p.balance.Float(),
+		})
+	}
+	return periods
+}
@@ -0,0 +1,200 @@
+package calculator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxSimulationPeriods caps the schedule periods of a simulation, paths
+// times months, to finish in a few seconds on a single core.
+const maxSimulationPeriods = 1_000_000
+
+// simulationOutcome is the result of one rate path.
+type simulationOutcome struct {
+	maxPayment  money.Money
+	overpayment money.Money
+}
+
+func (c *calculatorImpl) Simulate(ctx context.Context, req *model.SimulationRequest) (*model.SimulationResult, error) {
+	program, err := c.program(req.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Paths*req.Months > maxSimulationPeriods {
+		return nil, ErrSimulationTooLarge
+	}
+
+	objectCost := money.FromFloat(req.ObjectCost)
+	initialPayment := money.FromFloat(req.InitialPayment)
+	if !c.downPaymentCovers(objectCost, initialPayment, program.MinDownPayment) {
+		return nil, ErrInitialPaymentTooLow
+	}
+	if program.MaxMonths > 0 && req.Months > program.MaxMonths {
+		return nil, ErrTermTooLong
+	}
+	loanSum := objectCost - initialPayment
+	if loanSum <= 0 {
+		return nil, ErrNoLoan
+	}
+	if program.MaxLoan > 0 && loanSum > money.FromFloat(program.MaxLoan) {
+		return nil, ErrLoanTooLarge
+	}
+
+	dates := c.loanDates(nil, 0)
+	startRate, err := c.simulationStartRate(req.StartRate, dates.issue)
+	if err != nil {
+		return nil, err
+	}
+	meanRate := startRate
+	if req.MeanRate != nil {
+		meanRate = *req.MeanRate
+	}
+
+	params := scheduleParams{
+		loanSum:       loanSum,
+		months:        req.Months,
+		repaymentType: model.RepaymentAnnuity,
+		rounding:      c.rounding,
+		dates:         dates,
+	}
+
+	// Every path has its own generator seeded by the path number, so the
+	// result does not depend on how paths are spread over the workers
+	outcomes := make([]simulationOutcome, req.Paths)
+	paths := make(chan int)
+	var wg sync.WaitGroup
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range paths {
+				rng := rand.New(rand.NewPCG(req.Seed, uint64(i)))
+				path := params
+				path.rates = pathRates(keyRatePath(rng, req, startRate, meanRate), req.Spread)
+				schedule := buildSchedule(path)
+
+				// The closing payment only settles the remaining balance
+				outcome := simulationOutcome{overpayment: totalInterest(schedule), maxPayment: schedule[0].payment}
+				for _, p := range schedule[:len(schedule)-1] {
+					outcome.maxPayment = max(outcome.maxPayment, p.payment)
+				}
+				outcomes[i] = outcome
+			}
+		}()
+	}
+
+	err = feedPaths(ctx, paths, req.Paths)
+	wg.Wait()
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, ErrSimulationTimeout
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]money.Money, len(outcomes))
+	overpayments := make([]money.Money, len(outcomes))
+	for i, outcome := range outcomes {
+		payments[i], overpayments[i] = outcome.maxPayment, outcome.overpayment
+	}
+
+	return &model.SimulationResult{
+		Program:        selectedProgram(req.Program, program.ID),
+		Model:          req.Model,
+		Paths:          req.Paths,
+		Seed:           req.Seed,
+		StartRate:      startRate,
+		LoanSum:        loanSum.Float(),
+		MonthlyPayment: percentiles(payments),
+		Overpayment:    percentiles(overpayments),
+	}, nil
+}
+
+// feedPaths sends the path numbers to the workers until the context is done.
+func feedPaths(ctx context.Context, paths chan<- int, count int) error {
+	defer close(paths)
+	for i := range count {
+		select {
+		case paths <- i:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// simulationStartRate returns the requested start rate or the key rate on
+// the issue date.
+func (c *calculatorImpl) simulationStartRate(startRate *float64, issue time.Time) (float64, error) {
+	if startRate != nil {
+		return *startRate, nil
+	}
+	if c.keyRates == nil {
+		return 0, ErrKeyRateUnavailable
+	}
+	rate, ok := c.keyRates.RateOn(issue)
+	if !ok {
+		return 0, ErrKeyRateUnavailable
+	}
+	return rate, nil
+}
+
+// keyRatePath returns the key rate of every month. The monthly shock is the
+// annual volatility scaled by the square root of time, mean reversion pulls
+// the rate towards meanRate, the rate never falls below zero.
+func keyRatePath(rng *rand.Rand, req *model.SimulationRequest, startRate, meanRate float64) []float64 {
+	const dt = 1.0 / 12
+	shock := req.Volatility * math.Sqrt(dt)
+
+	path := make([]float64, req.Months)
+	rate := startRate
+	for i := range path {
+		path[i] = math.Round(rate*100) / 100
+		if req.Model == model.SimulationMeanReverting {
+			rate += req.Reversion * (meanRate - rate) * dt
+		}
+		rate = max(rate+shock*rng.NormFloat64(), 0)
+	}
+	return path
+}
+
+// pathRates charges the key rate of the path plus spread, floored at zero.
+func pathRates(path []float64, spread float64) rateFunc {
+	return func(period int, _ time.Time) float64 {
+		return max(path[period-1]+spread, 0)
+	}
+}
+
+// percentiles returns the nearest-rank percentiles and the mean of values.
+func percentiles(values []money.Money) model.Percentiles {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)].Float()
+	}
+
+	var total money.Money
+	for _, v := range sorted {
+		total += v
+	}
+
+	return model.Percentiles{
+		P5:   rank(5),
+		P25:  rank(25),
+		P50:  rank(50),
+		P75:  rank(75),
+		P95:  rank(95),
+		Mean: total.Div(int64(len(sorted)), money.HalfUp).Float(),
+	}
+}
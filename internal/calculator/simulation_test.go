@@ -0,0 +1,152 @@
+package calculator
+
+import (
+	"context"
+	"errors"
+	"mortgage-calculator/internal/keyrate"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+	"time"
+)
+
+func simulationRequest(modelName string, seed uint64) *model.SimulationRequest {
+	return &model.SimulationRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Base: true},
+		Spread:         2,
+		Model:          modelName,
+		Paths:          200,
+		Seed:           seed,
+		Volatility:     2,
+		Reversion:      0.5,
+	}
+}
+
+func TestCalculator_Simulate(t *testing.T) {
+	history := keyrate.NewHistory(map[time.Time]float64{date(2024, 1, 1): 16})
+	calc := NewCalculator(WithKeyRates(history), WithClock(fixedClock(date(2025, 3, 10))))
+
+	for _, modelName := range []string{model.SimulationRandomWalk, model.SimulationMeanReverting} {
+		t.Run(modelName, func(t *testing.T) {
+			result, err := calc.Simulate(context.Background(), simulationRequest(modelName, 42))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.StartRate != 16 {
+				t.Errorf("Expected start rate 16, got %v", result.StartRate)
+			}
+			for _, p := range []model.Percentiles{result.MonthlyPayment, result.Overpayment} {
+				if !(p.P5 <= p.P25 && p.P25 <= p.P50 && p.P50 <= p.P75 && p.P75 <= p.P95) {
+					t.Errorf("Expected ordered percentiles, got %+v", p)
+				}
+				if p.P5 == p.P95 {
+					t.Errorf("Expected a spread of outcomes, got %+v", p)
+				}
+			}
+
+			// Equal seeds give equal results regardless of the workers
+			again, err := calc.Simulate(context.Background(), simulationRequest(modelName, 42))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *again != *result {
+				t.Errorf("Expected equal results for the same seed, got %+v and %+v", result, again)
+			}
+
+			other, err := calc.Simulate(context.Background(), simulationRequest(modelName, 7))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if other.Overpayment == result.Overpayment {
+				t.Errorf("Expected different results for another seed, got %+v", other.Overpayment)
+			}
+		})
+	}
+}
+
+func TestCalculator_SimulateWithoutVolatility(t *testing.T) {
+	startRate := 14.0
+	req := simulationRequest(model.SimulationRandomWalk, 1)
+	req.StartRate = &startRate
+	req.Volatility = 0
+
+	result, err := NewCalculator().Simulate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Every path is the fixed rate of 16%
+	want := annuityPayment(money.FromFloat(4_000_000), 16, 240, money.DefaultRounding()).Float()
+	if p := result.MonthlyPayment; p.P5 != want || p.P95 != want || p.Mean != want {
+		t.Errorf("Expected every payment %.2f, got %+v", want, p)
+	}
+}
+
+func TestCalculator_SimulateErrors(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+
+	startRate := 16.0
+	withStart := simulationRequest(model.SimulationRandomWalk, 1)
+	withStart.StartRate = &startRate
+	tooLarge := simulationRequest(model.SimulationRandomWalk, 1)
+	tooLarge.Months, tooLarge.Paths = 600, 10_000
+	noLoan := simulationRequest(model.SimulationRandomWalk, 1)
+	noLoan.InitialPayment = 5_500_000
+	noLoan.StartRate = &startRate
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     *model.SimulationRequest
+		wantErr error
+	}{
+		{"cancelled context", cancelled, withStart, context.Canceled},
+		{"deadline exceeded", expired, withStart, ErrSimulationTimeout},
+		{"too many periods", context.Background(), tooLarge, ErrSimulationTooLarge},
+		{"initial payment above the object cost", context.Background(), noLoan, ErrNoLoan},
+		{"no key rate history", context.Background(), simulationRequest(model.SimulationRandomWalk, 1), ErrKeyRateUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCalculator().Simulate(tt.ctx, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	values := make([]money.Money, 0, 100)
+	for i := 100; i >= 1; i-- {
+		values = append(values, money.FromFloat(float64(i)))
+	}
+
+	want := model.Percentiles{P5: 5, P25: 25, P50: 50, P75: 75, P95: 95, Mean: 50.5}
+	if got := percentiles(values); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// BenchmarkSimulate runs the largest simulation accepted by the calculator.
+func BenchmarkSimulate(b *testing.B) {
+	startRate := 16.0
+	req := simulationRequest(model.SimulationMeanReverting, 42)
+	req.StartRate = &startRate
+	req.Months = maxTerm
+	req.Paths = maxSimulationPeriods / maxTerm
+	calc := NewCalculator()
+
+	for b.Loop() {
+		if _, err := calc.Simulate(context.Background(), req); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package calculator
+
+import (
+	"fmt"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// maxTerm is the longest term accepted by MortgageRequest validation.
+const maxTerm = 600
+
+func (c *calculatorImpl) Solve(req *model.SolveRequest) (*model.SolveResult, error) {
+	program, err := c.program(req.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SolveResult{
+		Solve:   req.Solve,
+		Program: selectedProgram(req.Program, program.ID),
+		Rate:    program.Rate,
+	}
+
+	maxMonths := maxTerm
+	if program.MaxMonths > 0 && program.MaxMonths < maxMonths {
+		maxMonths = program.MaxMonths
+	}
+	if grid := program.RateGrid; grid != nil {
+		maxMonths = min(maxMonths, grid.TermBands[len(grid.TermBands)-1])
+	}
+
+	switch req.Solve {
+	case model.SolveTerm:
+		return c.solveTerm(req, program, maxMonths, result)
+	case model.SolveDownPayment:
+		return c.solveDownPayment(req, program, maxMonths, result)
+	default:
+		return nil, ErrUnknownSolveTarget
+	}
+}
+
+// solveTerm finds the shortest term whose payment does not exceed the target.
+func (c *calculatorImpl) solveTerm(req *model.SolveRequest, program model.Program, maxMonths int, result *model.SolveResult) (*model.SolveResult, error) {
+	objectCost := money.FromFloat(req.ObjectCost)
+	initialPayment := money.FromFloat(req.InitialPayment)
+	if !c.downPaymentCovers(objectCost, initialPayment, program.MinDownPayment) {
+		return nil, ErrInitialPaymentTooLow
+	}
+
+	loanSum := objectCost - initialPayment
+	if loanSum <= 0 {
+		return nil, ErrNoLoan
+	}
+	if program.MaxLoan > 0 && loanSum > money.FromFloat(program.MaxLoan) {
+		return nil, ErrLoanTooLarge
+	}
+
+	target := money.FromFloat(req.TargetPayment)
+	result.InitialPayment = initialPayment.Float()
+	result.LoanSum = loanSum.Float()
+
+	// The payment decreases with the term at a flat rate, but a rate grid may
+	// raise the rate of longer terms, so the terms are checked one by one
+	rate := program.Rate
+	for months := 1; months <= maxMonths; months++ {
+		monthsRate, tier, err := c.programRate(program, loanSum, objectCost, months)
+		if err != nil {
+			return nil, err
+		}
+		rate = monthsRate
+
+		if payment := c.programPayment(program, rate, loanSum, months); payment <= target {
+			result.Feasible = true
+			result.Rate = rate
+			result.RateTier = tier
+			result.Months = months
+			result.MonthlyPayment = payment.Float()
+			return result, nil
+		}
+	}
+
+	// Above the subsidy limit the excess accrues at its own rate
+	subsidized, excess := splitAtLimit(program, loanSum)
+	interest := subsidized.Percent(rate, 1, 12, c.rounding.Mode) + excess.Percent(program.ExcessRate, 1, 12, c.rounding.Mode)
+	if target <= interest {
+		result.Reason = model.ReasonPaymentBelowInterest
+		result.Details = fmt.Sprintf("target payment %s does not cover the monthly interest %s", target, interest)
+	} else {
+		result.Reason = model.ReasonTermOutOfRange
+		result.Details = fmt.Sprintf("target payment requires a term longer than %d months", maxMonths)
+	}
+	return result, nil
+}
+
+// solveDownPayment finds the smallest down payment that gives the target
+// payment for the term, never below the program minimum share.
+func (c *calculatorImpl) solveDownPayment(req *model.SolveRequest, program model.Program, maxMonths int, result *model.SolveResult) (*model.SolveResult, error) {
+	if req.Months > maxMonths {
+		result.Reason = model.ReasonTermOutOfRange
+		result.Details = fmt.Sprintf("program term is limited to %d months", maxMonths)
+		return result, nil
+	}
+
+	objectCost := money.FromFloat(req.ObjectCost)
+	target := money.FromFloat(req.TargetPayment)
+
+	loanAt := func(rate float64) money.Money {
+		loanSum := money.Min(c.maxProgramLoan(program, rate, target, req.Months), objectCost)
+		if program.MaxLoan > 0 {
+			loanSum = money.Min(loanSum, money.FromFloat(program.MaxLoan))
+		}
+		return loanSum
+	}
+
+	// With a rate grid the loan-to-value band defines the rate, the largest
+	// loan staying in its band wins
+	var loanSum money.Money
+	if program.RateGrid != nil {
+		objectCostOf := func(money.Money) money.Money {
+			return objectCost
+		}
+		var err error
+		if loanSum, _, err = c.bestTier(program.RateGrid, program.Rate, req.Months, loanAt, objectCostOf); err != nil {
+			return nil, err
+		}
+	} else {
+		loanSum = loanAt(program.Rate)
+	}
+	if loanSum <= 0 {
+		result.Reason = model.ReasonPaymentTooLow
+		result.Details = fmt.Sprintf("target payment %s does not repay any loan in %d months", target, req.Months)
+		return result, nil
+	}
+
+	initialPayment := objectCost - loanSum
+	if !c.downPaymentCovers(objectCost, initialPayment, program.MinDownPayment) {
+		initialPayment = objectCost.Percent(program.MinDownPayment, 100, 1, c.rounding.Mode)
+		loanSum = objectCost - initialPayment
+	}
+
+	// The minimum down payment may move the loan to a lower band
+	rate, tier, err := c.programRate(program, loanSum, objectCost, req.Months)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Feasible = true
+	result.Rate = rate
+	result.RateTier = tier
+	result.Months = req.Months
+	result.InitialPayment = initialPayment.Float()
+	result.LoanSum = loanSum.Float()
+	result.MonthlyPayment = c.programPayment(program, rate, loanSum, req.Months).Float()
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"testing"
+)
+
+func TestCalculator_Solve(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name           string
+		request        *model.SolveRequest
+		wantFeasible   bool
+		wantReason     string
+		wantMonths     int
+		wantInitial    float64
+		wantMaxPayment float64
+		wantError      error
+	}{
+		{
+			name:           "term for target payment",
+			request:        &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 33458, ObjectCost: 5_000_000, InitialPayment: 1_000_000, Program: model.MortgageProgram{Salary: true}},
+			wantFeasible:   true,
+			wantMonths:     240,
+			wantInitial:    1_000_000,
+			wantMaxPayment: 33458,
+		},
+		{
+			name:         "target below monthly interest",
+			request:      &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 20_000, ObjectCost: 5_000_000, InitialPayment: 1_000_000, Program: model.MortgageProgram{Salary: true}},
+			wantFeasible: false,
+			wantReason:   model.ReasonPaymentBelowInterest,
+		},
+		{
+			name:         "target needs more than 600 months",
+			request:      &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 27_000, ObjectCost: 5_000_000, InitialPayment: 1_000_000, Program: model.MortgageProgram{Salary: true}},
+			wantFeasible: false,
+			wantReason:   model.ReasonTermOutOfRange,
+		},
+		{
+			name:      "term with too low initial payment",
+			request:   &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 33458, ObjectCost: 5_000_000, InitialPayment: 500_000, Program: model.MortgageProgram{Salary: true}},
+			wantError: ErrInitialPaymentTooLow,
+		},
+		{
+			name:      "term with initial payment covering the cost",
+			request:   &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 1_000, ObjectCost: 5_000_000, InitialPayment: 5_000_000, Program: model.MortgageProgram{Salary: true}},
+			wantError: ErrNoLoan,
+		},
+		{
+			name:      "term with initial payment above the cost",
+			request:   &model.SolveRequest{Solve: model.SolveTerm, TargetPayment: 1_000, ObjectCost: 1_000_000, InitialPayment: 1_200_000, Program: model.MortgageProgram{Salary: true}},
+			wantError: ErrNoLoan,
+		},
+		{
+			name:           "down payment for target payment",
+			request:        &model.SolveRequest{Solve: model.SolveDownPayment, TargetPayment: 25_000, ObjectCost: 5_000_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantFeasible:   true,
+			wantMonths:     240,
+			wantInitial:    2_011_143,
+			wantMaxPayment: 25_000,
+		},
+		{
+			name:           "down payment never below program minimum",
+			request:        &model.SolveRequest{Solve: model.SolveDownPayment, TargetPayment: 100_000, ObjectCost: 5_000_000, Months: 240, Program: model.MortgageProgram{Salary: true}},
+			wantFeasible:   true,
+			wantMonths:     240,
+			wantInitial:    1_000_000,
+			wantMaxPayment: 100_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Solve(tt.request)
+
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Errorf("Expected error %v, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Feasible != tt.wantFeasible || result.Reason != tt.wantReason {
+				t.Fatalf("Expected feasible %v (%s), got %v (%s: %s)", tt.wantFeasible, tt.wantReason, result.Feasible, result.Reason, result.Details)
+			}
+			if !result.Feasible {
+				return
+			}
+
+			if result.Months != tt.wantMonths {
+				t.Errorf("Expected %d months, got %d", tt.wantMonths, result.Months)
+			}
+			if result.InitialPayment != tt.wantInitial {
+				t.Errorf("Expected initial payment %f, got %f", tt.wantInitial, result.InitialPayment)
+			}
+			if result.MonthlyPayment > tt.wantMaxPayment {
+				t.Errorf("Payment %f exceeds target %f", result.MonthlyPayment, tt.wantMaxPayment)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// splitSubsidies returns the total of subsidies applied to the down payment
+// and the subsidies applied to the principal as prepayments.
+func splitSubsidies(subsidies []model.Subsidy) (money.Money, []model.Prepayment) {
+	var downPayment money.Money
+	var prepayments []model.Prepayment
+
+	for _, subsidy := range subsidies {
+		if subsidy.AppliedTo == model.SubsidyToDownPayment {
+			downPayment += money.FromFloat(subsidy.Amount)
+			continue
+		}
+
+		strategy := subsidy.Strategy
+		if strategy == "" {
+			strategy = model.PrepaymentReduceTerm
+		}
+		prepayments = append(prepayments, model.Prepayment{
+			Month:    subsidy.Month,
+			Date:     subsidy.Date,
+			Amount:   subsidy.Amount,
+			Strategy: strategy,
+		})
+	}
+
+	return downPayment, prepayments
+}
@@ -0,0 +1,100 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"testing"
+)
+
+func TestCalculator_CalculateSubsidies(t *testing.T) {
+	calc := NewCalculator()
+
+	tests := []struct {
+		name             string
+		initialPayment   float64
+		subsidies        []model.Subsidy
+		wantErr          error
+		wantLoanSum      float64
+		wantDownSubsidy  float64
+		wantTotalPrepaid float64
+	}{
+		{
+			name:           "maternity capital completes the down payment",
+			initialPayment: 500_000,
+			subsidies: []model.Subsidy{
+				{Type: model.SubsidyMaternityCapital, Amount: 500_000, AppliedTo: model.SubsidyToDownPayment},
+			},
+			wantLoanSum:     4_000_000,
+			wantDownSubsidy: 500_000,
+		},
+		{
+			name:           "later subsidy does not count towards the down payment",
+			initialPayment: 500_000,
+			subsidies: []model.Subsidy{
+				{Type: model.SubsidyMaternityCapital, Amount: 500_000, AppliedTo: model.SubsidyToPrincipal, Month: 12},
+			},
+			wantErr: ErrInitialPaymentTooLow,
+		},
+		{
+			name:           "regional subsidy repays the principal",
+			initialPayment: 1_000_000,
+			subsidies: []model.Subsidy{
+				{Type: model.SubsidyRegional, Amount: 450_000, AppliedTo: model.SubsidyToPrincipal, Month: 12},
+			},
+			wantLoanSum:      4_000_000,
+			wantTotalPrepaid: 450_000,
+		},
+		{
+			name:           "principal subsidy without a month",
+			initialPayment: 1_000_000,
+			subsidies: []model.Subsidy{
+				{Type: model.SubsidyRegional, Amount: 450_000, AppliedTo: model.SubsidyToPrincipal},
+			},
+			wantErr: ErrPrepaymentWithoutDate,
+		},
+		{
+			name:           "subsidies cover the whole cost",
+			initialPayment: 1_000_000,
+			subsidies: []model.Subsidy{
+				{Type: model.SubsidyRegional, Amount: 4_000_000, AppliedTo: model.SubsidyToDownPayment},
+			},
+			wantErr: ErrSubsidiesExceedCost,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: tt.initialPayment,
+				Months:         240,
+				Program:        model.MortgageProgram{Salary: true},
+				Subsidies:      tt.subsidies,
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := result.Aggregates.LoanSum; got != tt.wantLoanSum {
+				t.Errorf("Expected loan sum %f, got %f", tt.wantLoanSum, got)
+			}
+			if got := result.Aggregates.DownPaymentSubsidies; got != tt.wantDownSubsidy {
+				t.Errorf("Expected down payment subsidies %f, got %f", tt.wantDownSubsidy, got)
+			}
+
+			var totalPrepaid float64
+			if result.EarlyRepayment != nil {
+				totalPrepaid = result.EarlyRepayment.TotalPrepaid
+			}
+			if totalPrepaid != tt.wantTotalPrepaid {
+				t.Errorf("Expected total prepaid %f, got %f", tt.wantTotalPrepaid, totalPrepaid)
+			}
+		})
+	}
+}
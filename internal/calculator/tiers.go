@@ -0,0 +1,107 @@
+package calculator
+
+import (
+	"math"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"sort"
+)
+
+// rateTier finds the cell of the rate grid for the loan. The loan-to-value
+// band is checked in money, so a loan of exactly 80% fits the 0.8 band.
+func (c *calculatorImpl) rateTier(grid *model.RateGrid, loanSum, objectCost money.Money, months int) (*model.RateTier, error) {
+	ltvBand := -1
+	for i, band := range grid.LTVBands {
+		if loanSum <= objectCost.Percent(band, 100, 1, c.rounding.Mode) {
+			ltvBand = i
+			break
+		}
+	}
+
+	termBand := -1
+	for j, band := range grid.TermBands {
+		if months <= band {
+			termBand = j
+			break
+		}
+	}
+
+	if ltvBand < 0 || termBand < 0 {
+		return nil, ErrNoRateTier
+	}
+
+	return &model.RateTier{
+		LTV:        math.Round(loanSum.Float()/objectCost.Float()*1e4) / 1e4,
+		MaxLTV:     grid.LTVBands[ltvBand],
+		MaxMonths:  grid.TermBands[termBand],
+		Adjustment: grid.Adjustments[ltvBand][termBand],
+	}, nil
+}
+
+// programRate returns the program rate of the loan, adjusted by the tier of
+// the rate grid when the program has one.
+func (c *calculatorImpl) programRate(program model.Program, loanSum, objectCost money.Money, months int) (float64, *model.RateTier, error) {
+	if program.RateGrid == nil {
+		return program.Rate, nil, nil
+	}
+
+	tier, err := c.rateTier(program.RateGrid, loanSum, objectCost, months)
+	if err != nil {
+		return 0, nil, err
+	}
+	return adjustRate(program.Rate, tier.Adjustment), tier, nil
+}
+
+// bestTier finds the tier giving the largest loan when the loan-to-value
+// depends on the loan itself. loan returns the largest loan at a rate, it is
+// cut to the band edge and only counts when it resolves to the same band.
+// The tier is nil when no band gives a loan.
+func (c *calculatorImpl) bestTier(grid *model.RateGrid, rate float64, months int, loan func(rate float64) money.Money, objectCost func(loan money.Money) money.Money) (money.Money, *model.RateTier, error) {
+	termBand := -1
+	for j, band := range grid.TermBands {
+		if months <= band {
+			termBand = j
+			break
+		}
+	}
+	if termBand < 0 {
+		return 0, nil, ErrNoRateTier
+	}
+
+	var best money.Money
+	var bestTier *model.RateTier
+	for i, band := range grid.LTVBands {
+		candidate := c.ltvEdge(band, loan(adjustRate(rate, grid.Adjustments[i][termBand])), objectCost)
+		if candidate <= best {
+			continue
+		}
+		tier, err := c.rateTier(grid, candidate, objectCost(candidate), months)
+		if err != nil || tier.MaxLTV != band {
+			continue
+		}
+		best, bestTier = candidate, tier
+	}
+	return best, bestTier, nil
+}
+
+// ltvEdge returns the largest loan up to loan, in whole rubles when cut,
+// whose share of the object cost fits into the band.
+func (c *calculatorImpl) ltvEdge(band float64, loan money.Money, objectCost func(loan money.Money) money.Money) money.Money {
+	fits := func(loan money.Money) bool {
+		return loan <= objectCost(loan).Percent(band, 100, 1, c.rounding.Mode)
+	}
+	if fits(loan) {
+		return loan
+	}
+
+	rubles := sort.Search(int(loan/money.Ruble), func(i int) bool {
+		return !fits(money.Money(i) * money.Ruble)
+	})
+	return money.Money(rubles-1) * money.Ruble
+}
+
+// adjustRate adds percentage points to the rate, the sum is rounded to
+// hundredths of a percent to drop the binary representation error.
+func adjustRate(rate, adjustment float64) float64 {
+	return math.Round((rate+adjustment)*100) / 100
+}
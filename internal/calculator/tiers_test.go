@@ -0,0 +1,242 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"testing"
+)
+
+func TestCalculator_CalculateRateTier(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{{
+		ID:             "tiered",
+		Rate:           10,
+		MinDownPayment: 0.1,
+		RateGrid: &model.RateGrid{
+			LTVBands:  []float64{0.5, 0.8},
+			TermBands: []int{120, 360},
+			Adjustments: [][]float64{
+				{-1, -0.7},
+				{0, 0.3},
+			},
+		},
+	}}))
+
+	tests := []struct {
+		name           string
+		initialPayment float64
+		months         int
+		wantErr        error
+		wantRate       float64
+		wantTier       model.RateTier
+	}{
+		{
+			name:           "low LTV and short term discount",
+			initialPayment: 3_000_000,
+			months:         120,
+			wantRate:       9,
+			wantTier:       model.RateTier{LTV: 0.4, MaxLTV: 0.5, MaxMonths: 120, Adjustment: -1},
+		},
+		{
+			name:           "band bounds are inclusive",
+			initialPayment: 2_500_000,
+			months:         360,
+			wantRate:       9.3,
+			wantTier:       model.RateTier{LTV: 0.5, MaxLTV: 0.5, MaxMonths: 360, Adjustment: -0.7},
+		},
+		{
+			name:           "high LTV surcharge",
+			initialPayment: 1_000_000,
+			months:         240,
+			wantRate:       10.3,
+			wantTier:       model.RateTier{LTV: 0.8, MaxLTV: 0.8, MaxMonths: 360, Adjustment: 0.3},
+		},
+		{
+			name:           "LTV above the grid",
+			initialPayment: 750_000,
+			months:         240,
+			wantErr:        ErrNoRateTier,
+		},
+		{
+			name:           "term above the grid",
+			initialPayment: 1_000_000,
+			months:         480,
+			wantErr:        ErrNoRateTier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: tt.initialPayment,
+				Months:         tt.months,
+				Program:        model.MortgageProgram{ID: "tiered"},
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := result.Aggregates.Rate; got != tt.wantRate {
+				t.Errorf("Expected rate %v, got %v", tt.wantRate, got)
+			}
+			if result.RateTier == nil || *result.RateTier != tt.wantTier {
+				t.Errorf("Expected tier %+v, got %+v", tt.wantTier, result.RateTier)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateWithoutRateGrid(t *testing.T) {
+	result, err := NewCalculator().Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{Base: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.RateTier != nil {
+		t.Errorf("Expected no rate tier, got %+v", result.RateTier)
+	}
+}
+
+// gridProgram is the base program of config.yml with its rate grid.
+func gridProgram() model.Program {
+	return model.Program{
+		ID:             model.ProgramBase,
+		Rate:           10,
+		MinDownPayment: 0.2,
+		RateGrid: &model.RateGrid{
+			LTVBands:  []float64{0.5, 0.7, 0.8},
+			TermBands: []int{120, 240, 600},
+			Adjustments: [][]float64{
+				{-1, -0.7, -0.5},
+				{-0.5, -0.3, 0},
+				{-0.2, 0, 0.3},
+			},
+		},
+	}
+}
+
+func TestCalculator_AffordabilityRateTier(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{gridProgram()}))
+
+	tests := []struct {
+		name          string
+		maxPayment    float64
+		wantRate      float64
+		wantLimitedBy string
+	}{
+		{"budget reaches the highest band", 50_000, 10.3, model.LimitedByPayment},
+		{"budget fits the lowest band", 15_000, 9.5, model.LimitedByPayment},
+		{"larger loan moves to a more expensive band", 17_700, 9.5, model.LimitedByRateTier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Affordability(&model.AffordabilityRequest{
+				MaxMonthlyPayment: tt.maxPayment,
+				DownPayment:       2_000_000,
+				Months:            300,
+				Program:           model.MortgageProgram{Base: true},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Rate != tt.wantRate || result.LimitedBy != tt.wantLimitedBy {
+				t.Errorf("Expected rate %v limited by %s, got %v limited by %s", tt.wantRate, tt.wantLimitedBy, result.Rate, result.LimitedBy)
+			}
+
+			// The calculation of the found property lands in the same tier
+			calculation, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     result.MaxObjectCost,
+				InitialPayment: result.DownPayment,
+				Months:         result.Months,
+				Program:        model.MortgageProgram{Base: true},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if calculation.Aggregates.Rate != result.Rate || calculation.Aggregates.MonthlyPayment != result.MonthlyPayment {
+				t.Errorf("Expected rate %v and payment %v in the calculation, got %v and %v",
+					result.Rate, result.MonthlyPayment, calculation.Aggregates.Rate, calculation.Aggregates.MonthlyPayment)
+			}
+			if calculation.Aggregates.MonthlyPayment > tt.maxPayment {
+				t.Errorf("Expected payment within %v, got %v", tt.maxPayment, calculation.Aggregates.MonthlyPayment)
+			}
+		})
+	}
+}
+
+func TestCalculator_SolveRateTier(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{gridProgram()}))
+
+	term, err := calc.Solve(&model.SolveRequest{
+		Solve:          model.SolveTerm,
+		TargetPayment:  35_000,
+		ObjectCost:     5_000_000,
+		InitialPayment: 2_000_000,
+		Program:        model.MortgageProgram{Base: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// LTV 0.6 is in the 0.7 band, the term in the 240 months band
+	if !term.Feasible || term.Rate != 9.7 || term.RateTier == nil || term.RateTier.MaxMonths != 240 {
+		t.Errorf("Expected a feasible term at 9.7%% in the 240 months band, got %+v", term)
+	}
+
+	down, err := calc.Solve(&model.SolveRequest{
+		Solve:         model.SolveDownPayment,
+		TargetPayment: 30_000,
+		ObjectCost:    5_000_000,
+		Months:        300,
+		Program:       model.MortgageProgram{Base: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calculation, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: down.InitialPayment,
+		Months:         300,
+		Program:        model.MortgageProgram{Base: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !down.Feasible || calculation.Aggregates.Rate != down.Rate || calculation.Aggregates.MonthlyPayment > 30_000 {
+		t.Errorf("Expected the solved down payment to keep the payment within 30000 at %v%%, got %v at %v%%",
+			down.Rate, calculation.Aggregates.MonthlyPayment, calculation.Aggregates.Rate)
+	}
+}
+
+func TestCalculator_RefinanceRateTier(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{gridProgram()}))
+	request := model.RefinanceRequest{
+		Balance:         3_000_000,
+		Rate:            16,
+		RemainingMonths: 120,
+		Program:         model.MortgageProgram{Base: true},
+	}
+
+	if _, err := calc.Refinance(&request); !errors.Is(err, ErrObjectCostRequired) {
+		t.Errorf("Expected error %v, got %v", ErrObjectCostRequired, err)
+	}
+
+	request.ObjectCost = 10_000_000
+	result, err := calc.Refinance(&request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Offer.Rate != 9 || result.RateTier == nil || result.RateTier.MaxLTV != 0.5 {
+		t.Errorf("Expected offer rate 9 in the 0.5 band, got %v and %+v", result.Offer.Rate, result.RateTier)
+	}
+}
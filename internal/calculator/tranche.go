@@ -0,0 +1,106 @@
+package calculator
+
+import (
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+)
+
+// tranche is a part of a combined loan repaid with its own rate.
+type tranche struct {
+	kind     string
+	schedule []loanPeriod
+}
+
+// splitLoan issues the loan up to the subsidy limit at the program rate and
+// the excess at the excess rate, both over the same term and dates.
+func splitLoan(params scheduleParams, limit money.Money, excessRate float64) []tranche {
+	subsidized, excess := params, params
+	subsidized.loanSum = limit
+	excess.loanSum = params.loanSum - limit
+	excess.rates = fixedRate(excessRate)
+
+	return []tranche{
+		{kind: model.TrancheSubsidized, schedule: buildSchedule(subsidized)},
+		{kind: model.TrancheExcess, schedule: buildSchedule(excess)},
+	}
+}
+
+// splitAtLimit splits the loan at the program subsidy limit, the excess is
+// zero within the limit and for programs without one.
+func splitAtLimit(program model.Program, loanSum money.Money) (subsidized, excess money.Money) {
+	if limit := money.FromFloat(program.SubsidyLimit); limit > 0 && loanSum > limit {
+		return limit, loanSum - limit
+	}
+	return loanSum, 0
+}
+
+// programPayment returns the annuity of the loan at the rate, a loan above
+// the subsidy limit pays the annuities of both tranches.
+func (c *calculatorImpl) programPayment(program model.Program, rate float64, loanSum money.Money, months int) money.Money {
+	subsidized, excess := splitAtLimit(program, loanSum)
+	payment := annuityPayment(subsidized, rate, months, c.rounding)
+	if excess > 0 {
+		payment += annuityPayment(excess, program.ExcessRate, months, c.rounding)
+	}
+	return payment
+}
+
+// maxProgramLoan returns the largest loan in whole rubles whose
+// programPayment does not exceed payment. Above the subsidy limit the rest
+// of the payment goes to the excess tranche.
+func (c *calculatorImpl) maxProgramLoan(program model.Program, rate float64, payment money.Money, months int) money.Money {
+	loan := maxLoanByPayment(payment, rate, months, c.rounding)
+	subsidized, excess := splitAtLimit(program, loan)
+	if excess == 0 {
+		return loan
+	}
+
+	rest := payment - annuityPayment(subsidized, rate, months, c.rounding)
+	return subsidized + maxLoanByPayment(rest, program.ExcessRate, months, c.rounding)
+}
+
+// combinedSchedule sums the tranche schedules period by period, the rate of
+// a combined period is the one of the subsidized tranche.
+func combinedSchedule(tranches []tranche) []loanPeriod {
+	var schedule []loanPeriod
+	for _, t := range tranches {
+		for i, p := range t.schedule {
+			if i == len(schedule) {
+				schedule = append(schedule, p)
+				continue
+			}
+			schedule[i].payment += p.payment
+			schedule[i].interest += p.interest
+			schedule[i].principal += p.principal
+			schedule[i].prepayment += p.prepayment
+			schedule[i].balance += p.balance
+		}
+	}
+	return schedule
+}
+
+// toModelTranches summarizes every tranche, the monthly payment is the first
+// one after the grace period.
+func toModelTranches(tranches []tranche, graceMonths int) []model.Tranche {
+	result := make([]model.Tranche, 0, len(tranches))
+	for _, t := range tranches {
+		var loanSum money.Money
+		for _, p := range t.schedule {
+			loanSum += p.principal + p.prepayment
+		}
+
+		regular := t.schedule[0]
+		if graceMonths > 0 && graceMonths < len(t.schedule) {
+			regular = t.schedule[graceMonths]
+		}
+
+		result = append(result, model.Tranche{
+			Type:           t.kind,
+			LoanSum:        loanSum.Float(),
+			Rate:           t.schedule[0].rate,
+			MonthlyPayment: regular.payment.Float(),
+			Overpayment:    totalInterest(t.schedule).Float(),
+		})
+	}
+	return result
+}
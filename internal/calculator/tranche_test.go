@@ -0,0 +1,181 @@
+package calculator
+
+import (
+	"errors"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
+	"testing"
+)
+
+func TestCalculator_CalculateCombinedLoan(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 3_000_000, ExcessRate: 10},
+	}))
+
+	tests := []struct {
+		name          string
+		repaymentType string
+		grace         *model.GracePeriod
+	}{
+		{name: "annuity", repaymentType: model.RepaymentAnnuity},
+		{name: "differentiated", repaymentType: model.RepaymentDifferentiated},
+		{name: "interest-only grace", repaymentType: model.RepaymentAnnuity, grace: &model.GracePeriod{Months: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Calculate(&model.MortgageRequest{
+				ObjectCost:     5_000_000,
+				InitialPayment: 1_000_000,
+				Months:         240,
+				Program:        model.MortgageProgram{ID: "family"},
+				RepaymentType:  tt.repaymentType,
+				Grace:          tt.grace,
+				Schedule:       true,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.Tranches) != 2 {
+				t.Fatalf("Expected 2 tranches, got %d", len(result.Tranches))
+			}
+			subsidized, excess := result.Tranches[0], result.Tranches[1]
+			if subsidized.LoanSum != 3_000_000 || subsidized.Rate != 6 {
+				t.Errorf("Expected subsidized 3000000 at 6%%, got %+v", subsidized)
+			}
+			if excess.LoanSum != 1_000_000 || excess.Rate != 10 {
+				t.Errorf("Expected excess 1000000 at 10%%, got %+v", excess)
+			}
+
+			aggregates := result.Aggregates
+			if got, want := money.FromFloat(aggregates.MonthlyPayment), money.FromFloat(subsidized.MonthlyPayment)+money.FromFloat(excess.MonthlyPayment); got != want {
+				t.Errorf("Expected combined payment %s, got %s", want, got)
+			}
+			if got, want := money.FromFloat(aggregates.Overpayment), money.FromFloat(subsidized.Overpayment)+money.FromFloat(excess.Overpayment); got != want {
+				t.Errorf("Expected combined overpayment %s, got %s", want, got)
+			}
+			if got := result.Schedule[len(result.Schedule)-1].Balance; got != 0 {
+				t.Errorf("Expected the combined loan to be repaid, got balance %f", got)
+			}
+		})
+	}
+}
+
+func TestCalculator_CalculateCombinedLoanLimits(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 3_000_000, ExcessRate: 10},
+	}))
+
+	result, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     3_500_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Tranches != nil {
+		t.Errorf("Expected a single loan below the limit, got %+v", result.Tranches)
+	}
+
+	_, err = calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     5_000_000,
+		InitialPayment: 1_000_000,
+		Months:         240,
+		Program:        model.MortgageProgram{ID: "family"},
+		Prepayments:    []model.Prepayment{{Month: 12, Amount: 100_000, Strategy: model.PrepaymentReduceTerm}},
+	})
+	if !errors.Is(err, ErrCombinedLoanUnsupported) {
+		t.Errorf("Expected error %v, got %v", ErrCombinedLoanUnsupported, err)
+	}
+}
+
+func TestCalculator_AffordabilityCombinedLoan(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 6_000_000, ExcessRate: 10},
+	}))
+
+	result, err := calc.Affordability(&model.AffordabilityRequest{
+		MaxMonthlyPayment: 100_000,
+		DownPayment:       5_000_000,
+		Months:            240,
+		Program:           model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.MaxLoan <= 6_000_000 || result.MonthlyPayment > 100_000 {
+		t.Fatalf("Expected a loan above the limit within the budget, got %v paying %v", result.MaxLoan, result.MonthlyPayment)
+	}
+
+	// The combined schedule of the found property fits into the budget
+	calculation, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     result.MaxObjectCost,
+		InitialPayment: result.DownPayment,
+		Months:         result.Months,
+		Program:        model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := calculation.Aggregates.MonthlyPayment; got != result.MonthlyPayment {
+		t.Errorf("Expected combined payment %v, got %v", result.MonthlyPayment, got)
+	}
+
+}
+
+func TestCalculator_SolveCombinedLoan(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 3_000_000, ExcessRate: 10},
+	}))
+
+	result, err := calc.Solve(&model.SolveRequest{
+		Solve:          model.SolveTerm,
+		TargetPayment:  45_000,
+		ObjectCost:     6_000_000,
+		InitialPayment: 1_200_000,
+		Program:        model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	calculation, err := calc.Calculate(&model.MortgageRequest{
+		ObjectCost:     6_000_000,
+		InitialPayment: 1_200_000,
+		Months:         result.Months,
+		Program:        model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Feasible || calculation.Aggregates.MonthlyPayment != result.MonthlyPayment || result.MonthlyPayment > 45_000 {
+		t.Errorf("Expected combined payment %v within 45000, got %+v", calculation.Aggregates.MonthlyPayment, result)
+	}
+}
+
+func TestCalculator_RefinanceCombinedLoan(t *testing.T) {
+	calc := NewCalculator(WithPrograms([]model.Program{
+		{ID: "family", Rate: 6, MinDownPayment: 0.2, SubsidyLimit: 3_000_000, ExcessRate: 10},
+	}))
+
+	result, err := calc.Refinance(&model.RefinanceRequest{
+		Balance:         4_000_000,
+		Rate:            16,
+		RemainingMonths: 240,
+		Program:         model.MortgageProgram{ID: "family"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	offer := result.Offer
+	if len(offer.Tranches) != 2 || offer.Tranches[0].LoanSum != 3_000_000 || offer.Tranches[1].LoanSum != 1_000_000 {
+		t.Fatalf("Expected tranches of 3000000 and 1000000, got %+v", offer.Tranches)
+	}
+	if got, want := offer.MonthlyPayment, offer.Tranches[0].MonthlyPayment+offer.Tranches[1].MonthlyPayment; got != want {
+		t.Errorf("Expected combined payment %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,141 @@
+package calendar
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Day types of the calendar file.
+const (
+	Holiday = "holiday"
+	Workday = "workday"
+)
+
+// Calendar knows the working days: Monday to Friday except holidays, plus
+// weekend days moved to work (e.g. Saturdays worked for a long holiday).
+// The calendar covers the whole years of its dates, outside them only
+// weekends are days off.
+type Calendar struct {
+	holidays map[time.Time]bool
+	workdays map[time.Time]bool
+	from, to time.Time
+}
+
+// New returns a calendar with the given holidays and working weekend days.
+func New(holidays, workdays []time.Time) *Calendar {
+	c := &Calendar{
+		holidays: make(map[time.Time]bool, len(holidays)),
+		workdays: make(map[time.Time]bool, len(workdays)),
+	}
+	for _, d := range holidays {
+		c.holidays[day(d)] = true
+		c.cover(d)
+	}
+	for _, d := range workdays {
+		c.workdays[day(d)] = true
+		c.cover(d)
+	}
+	return c
+}
+
+// cover extends the covered range to the whole year of the date.
+func (c *Calendar) cover(t time.Time) {
+	from := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(t.Year(), 12, 31, 0, 0, 0, 0, time.UTC)
+	if c.from.IsZero() || from.Before(c.from) {
+		c.from = from
+	}
+	if to.After(c.to) {
+		c.to = to
+	}
+}
+
+// Range returns the first and the last day of the covered years, both are
+// zero for an empty calendar.
+func (c *Calendar) Range() (from, to time.Time) {
+	return c.from, c.to
+}
+
+// Covers reports whether the holidays of the date are known.
+func (c *Calendar) Covers(t time.Time) bool {
+	d := day(t)
+	return !c.from.IsZero() && !d.Before(c.from) && !d.After(c.to)
+}
+
+// Load reads a CSV file with "date,type" rows, dates are YYYY-MM-DD, type is
+// holiday or workday and the first row is a header.
+func Load(path string) (*Calendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open holiday file: %w", err)
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read parses the calendar CSV from r, see Load.
+func Read(r io.Reader) (*Calendar, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read holidays: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("holiday file is empty")
+	}
+
+	var holidays, workdays []time.Time
+	for i, record := range records[1:] {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("holiday row %d: expected date and type", i+2)
+		}
+
+		date, err := time.Parse(dateLayout, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("holiday row %d: %w", i+2, err)
+		}
+
+		switch record[1] {
+		case Holiday:
+			holidays = append(holidays, date)
+		case Workday:
+			workdays = append(workdays, date)
+		default:
+			return nil, fmt.Errorf("holiday row %d: unknown day type %q", i+2, record[1])
+		}
+	}
+
+	return New(holidays, workdays), nil
+}
+
+// IsWorkday reports whether the bank works on the date.
+func (c *Calendar) IsWorkday(t time.Time) bool {
+	d := day(t)
+	if c.workdays[d] {
+		return true
+	}
+	if c.holidays[d] {
+		return false
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// NextWorkday returns the date itself when it is a working day, otherwise
+// the first working day after it.
+func (c *Calendar) NextWorkday(t time.Time) time.Time {
+	for !c.IsWorkday(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// day drops the time of day and location, calendar dates are compared as UTC.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar_NextWorkday(t *testing.T) {
+	cal, err := Read(strings.NewReader("date,type\n2024-01-01,holiday\n2024-01-08,holiday\n2024-11-02,workday\n2024-11-04,holiday\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want time.Time
+	}{
+		{"working day", time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{"weekend", time.Date(2024, 2, 17, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 19, 0, 0, 0, 0, time.UTC)},
+		{"holiday followed by workdays", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"weekend followed by holiday", time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC)},
+		{"working saturday", time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC)},
+		{"sunday before holiday", time.Date(2024, 11, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, 11, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.NextWorkday(tt.date); !got.Equal(tt.want) {
+				t.Errorf("NextWorkday() = %s, want %s", got.Format(dateLayout), tt.want.Format(dateLayout))
+			}
+		})
+	}
+}
+
+func TestRead_InvalidRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty file", ""},
+		{"invalid date", "date,type\n01.01.2024,holiday\n"},
+		{"unknown type", "date,type\n2024-01-01,vacation\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(tt.input)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestCalendar_Covers(t *testing.T) {
+	cal, err := Read(strings.NewReader("date,type\n2025-01-01,holiday\n2026-11-04,holiday\n2025-11-01,workday\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	from, to := cal.Range()
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("Expected range from %s, got %s", want.Format(dateLayout), from.Format(dateLayout))
+	}
+	if want := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("Expected range to %s, got %s", want.Format(dateLayout), to.Format(dateLayout))
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"before the first year", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{"first day", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"last day", time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC), true},
+		{"after the last year", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.Covers(tt.date); got != tt.want {
+				t.Errorf("Covers(%s) = %v, want %v", tt.date.Format(dateLayout), got, tt.want)
+			}
+		})
+	}
+
+	if New(nil, nil).Covers(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected empty calendar to cover no dates")
+	}
+}
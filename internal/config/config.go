@@ -1,14 +1,28 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	// "path/filepath"
+	"mortgage-calculator/internal/model"
+	"mortgage-calculator/internal/money"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Port int `mapstructure:"port"`
+	Port     int             `mapstructure:"port"`
+	Programs []model.Program `mapstructure:"programs"` // каталог ипотечных программ
+	Rounding money.Rounding  `mapstructure:"rounding"` // округление платежей
+	// KeyRateFile - CSV с историей ключевой ставки для плавающих ставок,
+	// пустое значение отключает плавающие ставки
+	KeyRateFile string `mapstructure:"key_rate_file"`
+	// HolidayFile - CSV производственного календаря, платежи в выходные и
+	// праздники переносятся на следующий рабочий день, пустое значение
+	// отключает перенос
+	HolidayFile string `mapstructure:"holiday_file"`
+	// DebtLoad - пороги показателя долговой нагрузки (ПДН) в процентах дохода
+	DebtLoad model.DebtLoadLimits `mapstructure:"debt_load"`
 }
 
 func LoadConfig(path string) (config *Config, err error) {
@@ -19,6 +33,10 @@ func LoadConfig(path string) (config *Config, err error) {
 
 	// Устанавливаем значения по умолчанию
 	viper.SetDefault("port", 8282)
+	viper.SetDefault("rounding.unit", "ruble")
+	viper.SetDefault("rounding.mode", string(money.HalfUp))
+	viper.SetDefault("debt_load.warning", 50)
+	viper.SetDefault("debt_load.max", 80)
 
 	// Пытаемся прочитать конфигурационный файл
 	err = viper.ReadInConfig()
@@ -42,6 +60,18 @@ func LoadConfig(path string) (config *Config, err error) {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
 
+	if err = config.Rounding.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rounding: %w", err)
+	}
+
+	if err = validatePrograms(config.Programs); err != nil {
+		return nil, fmt.Errorf("invalid programs: %w", err)
+	}
+
+	if err = validateDebtLoad(config.DebtLoad); err != nil {
+		return nil, fmt.Errorf("invalid debt_load: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -49,6 +79,10 @@ func LoadConfig(path string) (config *Config, err error) {
 func LoadConfigExplicit(configPath string) (config *Config, err error) {
 	viper.SetConfigFile(configPath) // Полный путь к файлу конфигурации
 	viper.SetDefault("port", 8282)
+	viper.SetDefault("rounding.unit", "ruble")
+	viper.SetDefault("rounding.mode", string(money.HalfUp))
+	viper.SetDefault("debt_load.warning", 50)
+	viper.SetDefault("debt_load.max", 80)
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -65,5 +99,98 @@ func LoadConfigExplicit(configPath string) (config *Config, err error) {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
 
+	if err = config.Rounding.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rounding: %w", err)
+	}
+
+	if err = validatePrograms(config.Programs); err != nil {
+		return nil, fmt.Errorf("invalid programs: %w", err)
+	}
+
+	if err = validateDebtLoad(config.DebtLoad); err != nil {
+		return nil, fmt.Errorf("invalid debt_load: %w", err)
+	}
+
 	return config, nil
 }
+
+// validatePrograms проверяет каталог программ: уникальные ID и неотрицательные
+// ставки, нулевая ставка допустима для субсидированных программ
+func validatePrograms(programs []model.Program) error {
+	ids := make(map[string]bool, len(programs))
+	for _, program := range programs {
+		if program.ID == "" {
+			return errors.New("program without id")
+		}
+		if ids[program.ID] {
+			return fmt.Errorf("duplicate program %q", program.ID)
+		}
+		ids[program.ID] = true
+
+		if program.Rate < 0 {
+			return fmt.Errorf("program %q has negative rate", program.ID)
+		}
+		if program.LifeInsuranceSurcharge < 0 {
+			return fmt.Errorf("program %q has negative life_insurance_surcharge", program.ID)
+		}
+		if program.MinDownPayment < 0 || program.MinDownPayment >= 1 {
+			return fmt.Errorf("program %q has invalid min_down_payment", program.ID)
+		}
+		if rules := program.Eligibility; rules != nil && rules.MaxAge > 0 && rules.MinAge > rules.MaxAge {
+			return fmt.Errorf("program %q has min_age above max_age", program.ID)
+		}
+		if program.SubsidyLimit < 0 {
+			return fmt.Errorf("program %q has negative subsidy_limit", program.ID)
+		}
+		if program.SubsidyLimit > 0 && program.ExcessRate <= 0 {
+			return fmt.Errorf("program %q needs excess_rate with subsidy_limit", program.ID)
+		}
+		if program.RateGrid != nil {
+			if err := validateRateGrid(program.Rate, program.RateGrid); err != nil {
+				return fmt.Errorf("program %q has invalid rate_grid: %w", program.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRateGrid проверяет сетку ставок: возрастающие границы диапазонов,
+// размер таблицы надбавок и неотрицательную итоговую ставку
+func validateRateGrid(rate float64, grid *model.RateGrid) error {
+	if len(grid.LTVBands) == 0 || len(grid.TermBands) == 0 {
+		return errors.New("ltv_bands and term_bands are required")
+	}
+	for i, band := range grid.LTVBands {
+		if band <= 0 || band > 1 || (i > 0 && band <= grid.LTVBands[i-1]) {
+			return errors.New("ltv_bands must ascend within (0, 1]")
+		}
+	}
+	for i, band := range grid.TermBands {
+		if band <= 0 || (i > 0 && band <= grid.TermBands[i-1]) {
+			return errors.New("term_bands must be positive and ascending")
+		}
+	}
+
+	if len(grid.Adjustments) != len(grid.LTVBands) {
+		return errors.New("adjustments must have a row for every ltv band")
+	}
+	for _, row := range grid.Adjustments {
+		if len(row) != len(grid.TermBands) {
+			return errors.New("adjustments must have a column for every term band")
+		}
+		for _, adjustment := range row {
+			if rate+adjustment < 0 {
+				return errors.New("adjusted rate is negative")
+			}
+		}
+	}
+	return nil
+}
+
+// validateDebtLoad проверяет пороги ПДН: 0 < warning <= max
+func validateDebtLoad(limits model.DebtLoadLimits) error {
+	if limits.Warning <= 0 || limits.Warning > limits.Max {
+		return fmt.Errorf("warning %v must be positive and not above max %v", limits.Warning, limits.Max)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,11 +9,16 @@ import (
 	"mortgage-calculator/internal/calculator"
 	"mortgage-calculator/internal/model"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 )
 
+// simulationTimeout stops a simulation in time to write the response within
+// the server WriteTimeout of 10 seconds.
+const simulationTimeout = 8 * time.Second
+
 type MortgageController struct {
 	calc  calculator.Calculator
 	cache cache.Cache
@@ -24,6 +30,12 @@ func NewMortgageController(calc calculator.Calculator, cache cache.Cache) *Mortg
 
 func (c *MortgageController) RegisterRoutes(r *chi.Mux) {
 	r.Post("/execute", c.handleCalculate)
+	r.Post("/affordability", c.handleAffordability)
+	r.Post("/solve", c.handleSolve)
+	r.Post("/compare", c.handleCompare)
+	r.Post("/refinance", c.handleRefinance)
+	r.Post("/simulate", c.handleSimulate)
+	r.Post("/eligibility", c.handleEligibility)
 	r.Get("/cache", c.handleGetCache)
 }
 
@@ -50,11 +62,7 @@ func (c *MortgageController) handleCalculate(w http.ResponseWriter, r *http.Requ
 	// Business logic calculation
 	result, err := c.calc.Calculate(&req)
 	if err != nil {
-		if errors.Is(err, calculator.ErrInitialPaymentTooLow) {
-			sendError(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		sendError(w, "internal server error", http.StatusInternalServerError)
+		sendCalculatorError(w, err)
 		return
 	}
 
@@ -66,6 +74,155 @@ func (c *MortgageController) handleCalculate(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(model.MortgageResponse{Result: result})
 }
 
+func (c *MortgageController) handleAffordability(w http.ResponseWriter, r *http.Request) {
+	var req model.AffordabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateProgram(req.Program); err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	result, err := c.calc.Affordability(&req)
+	if err != nil {
+		sendCalculatorError(w, err)
+		return
+	}
+
+	sendResult(w, result)
+}
+
+func (c *MortgageController) handleSolve(w http.ResponseWriter, r *http.Request) {
+	var req model.SolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateProgram(req.Program); err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	result, err := c.calc.Solve(&req)
+	if err != nil {
+		sendCalculatorError(w, err)
+		return
+	}
+
+	sendResult(w, result)
+}
+
+func (c *MortgageController) handleCompare(w http.ResponseWriter, r *http.Request) {
+	var req model.CompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	result, err := calculator.Compare(c.calc, &req)
+	if err != nil {
+		sendCalculatorError(w, err)
+		return
+	}
+
+	sendResult(w, result)
+}
+
+func (c *MortgageController) handleRefinance(w http.ResponseWriter, r *http.Request) {
+	var req model.RefinanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateProgram(req.Program); err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	result, err := c.calc.Refinance(&req)
+	if err != nil {
+		sendCalculatorError(w, err)
+		return
+	}
+
+	sendResult(w, result)
+}
+
+func (c *MortgageController) handleSimulate(w http.ResponseWriter, r *http.Request) {
+	var req model.SimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateProgram(req.Program); err != nil {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	// The simulation stops when the client goes away or the time is up
+	ctx, cancel := context.WithTimeout(r.Context(), simulationTimeout)
+	defer cancel()
+	result, err := c.calc.Simulate(ctx, &req)
+	if err != nil {
+		sendCalculatorError(w, err)
+		return
+	}
+
+	sendResult(w, result)
+}
+
+func (c *MortgageController) handleEligibility(w http.ResponseWriter, r *http.Request) {
+	var profile model.BorrowerProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		sendError(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(profile); err != nil {
+		sendValidationError(w, err)
+		return
+	}
+
+	sendResult(w, calculator.CheckEligibility(c.calc, &profile))
+}
+
 func (c *MortgageController) handleGetCache(w http.ResponseWriter, r *http.Request) {
 	calculations := c.cache.GetAll()
 	if len(calculations) == 0 {
@@ -80,6 +237,9 @@ func (c *MortgageController) handleGetCache(w http.ResponseWriter, r *http.Reque
 
 func validateProgram(program model.MortgageProgram) error {
 	count := 0
+	if program.ID != "" {
+		count++
+	}
 	if program.Salary {
 		count++
 	}
@@ -101,9 +261,29 @@ func validateProgram(program model.MortgageProgram) error {
 }
 
 func sendError(w http.ResponseWriter, message string, statusCode int) {
+	body, _ := json.Marshal(model.MortgageResponse{Error: message})
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(model.MortgageResponse{Error: message})
+	w.Write(body)
+}
+
+// sendResult writes a successful response in the {"result": ...} envelope.
+func sendResult(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(struct {
+		Result any `json:"result"`
+	}{Result: result})
+}
+
+// sendCalculatorError maps business errors to 400, everything else to 500.
+func sendCalculatorError(w http.ResponseWriter, err error) {
+	var businessErr *calculator.BusinessError
+	if errors.As(err, &businessErr) {
+		sendError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sendError(w, "internal server error", http.StatusInternalServerError)
 }
 
 func sendValidationError(w http.ResponseWriter, err error) {
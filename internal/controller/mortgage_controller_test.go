@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -23,6 +24,18 @@ import (
 type MockCalculator struct {
 	// result хранит результат, который должен вернуть мок
 	result *model.MortgageCalculation
+	// affordability хранит результат расчета доступной стоимости
+	affordability *model.AffordabilityResult
+	// solve хранит результат подбора срока или первоначального взноса
+	solve *model.SolveResult
+	// refinance хранит результат сравнения с рефинансированием
+	refinance *model.RefinanceResult
+	// simulation хранит результат моделирования плавающей ставки
+	simulation *model.SimulationResult
+	// simulationDeadline отмечает, что моделирование получило срок выполнения
+	simulationDeadline bool
+	// programs хранит каталог программ для сравнения
+	programs []model.Program
 	// err хранит ошибку, которую должен вернуть мок
 	err error
 }
@@ -34,6 +47,32 @@ func (m *MockCalculator) Calculate(req *model.MortgageRequest) (*model.MortgageC
 	return m.result, m.err
 }
 
+// Affordability - метод мока для расчета максимальной стоимости объекта
+func (m *MockCalculator) Affordability(req *model.AffordabilityRequest) (*model.AffordabilityResult, error) {
+	return m.affordability, m.err
+}
+
+// Solve - метод мока для подбора срока или первоначального взноса
+func (m *MockCalculator) Solve(req *model.SolveRequest) (*model.SolveResult, error) {
+	return m.solve, m.err
+}
+
+// Refinance - метод мока для расчета рефинансирования
+func (m *MockCalculator) Refinance(req *model.RefinanceRequest) (*model.RefinanceResult, error) {
+	return m.refinance, m.err
+}
+
+// Simulate - метод мока для моделирования плавающей ставки методом Монте-Карло
+func (m *MockCalculator) Simulate(ctx context.Context, req *model.SimulationRequest) (*model.SimulationResult, error) {
+	_, m.simulationDeadline = ctx.Deadline()
+	return m.simulation, m.err
+}
+
+// Programs - метод мока, возвращающий каталог программ
+func (m *MockCalculator) Programs() []model.Program {
+	return m.programs
+}
+
 // MockCache имитирует работу кэша для тестирования
 // Теперь он полностью имплементирует интерфейс Cache
 type MockCache struct {
@@ -152,6 +191,13 @@ func TestHandleCalculate(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `{"error":"initial payment too low"}`,
 		},
+		{
+			name:           "borrower is not eligible",
+			requestBody:    `{"object_cost": 10000000, "initial_payment": 2000000, "months": 240, "program": {"military": true}}`,
+			mockError:      &calculator.EligibilityError{Reasons: []string{model.ReasonMilitaryServiceRequired}}, // Причины отказа в тексте ошибки
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"borrower is not eligible for the program: military_service_required"}`,
+		},
 		{
 			name:           "internal calculator error",
 			requestBody:    `{"object_cost": 10000000, "initial_payment": 2000000, "months": 240, "program": {"base": true}}`,
@@ -274,6 +320,386 @@ func TestHandleCalculate(t *testing.T) {
 	}
 }
 
+// TestHandleAffordability тестирует расчет максимальной стоимости объекта по бюджету
+func TestHandleAffordability(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockResult     *model.AffordabilityResult
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful calculation",
+			requestBody: `{"max_monthly_payment": 50000, "down_payment": 2000000, "months": 240, "program": {"base": true}}`,
+			mockResult: &model.AffordabilityResult{
+				Program:        model.MortgageProgram{ID: "base", Base: true},
+				Rate:           10,
+				Months:         240,
+				DownPayment:    2000000,
+				MaxLoan:        5181000,
+				MaxObjectCost:  7181000,
+				MonthlyPayment: 49998,
+				LimitedBy:      model.LimitedByPayment,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"program":{"id":"base","salary":false,"military":false,"base":true},"rate":10,"months":240,"down_payment":2000000,"max_loan":5181000,"max_object_cost":7181000,"monthly_payment":49998,"limited_by":"payment"}}`,
+		},
+		{
+			name:           "invalid JSON syntax",
+			requestBody:    `{invalid json syntax`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid json"}`,
+		},
+		{
+			name:           "program not selected",
+			requestBody:    `{"max_monthly_payment": 50000, "down_payment": 2000000, "months": 240, "program": {}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"choose program"}`,
+		},
+		{
+			name:           "missing required field - max_monthly_payment",
+			requestBody:    `{"down_payment": 2000000, "months": 240, "program": {"base": true}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "monthly income instead of max_monthly_payment",
+			requestBody:    `{"monthly_income": 150000, "debt_payments": 20000, "down_payment": 2000000, "months": 240, "program": {"base": true}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "business error from calculator",
+			requestBody:    `{"max_monthly_payment": 50000, "months": 240, "program": {"base": true}}`,
+			mockError:      calculator.ErrNotAffordable,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"no loan fits the budget"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &MortgageController{
+				calc:  &MockCalculator{affordability: tt.mockResult, err: tt.mockError},
+				cache: &MockCache{},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/affordability", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleAffordability(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+// TestHandleSolve тестирует подбор срока и первоначального взноса под целевой платеж
+func TestHandleSolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockResult     *model.SolveResult
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "infeasible target is not an error",
+			requestBody: `{"solve": "term", "target_payment": 20000, "object_cost": 5000000, "initial_payment": 1000000, "program": {"salary": true}}`,
+			mockResult: &model.SolveResult{
+				Solve:    model.SolveTerm,
+				Program:  model.MortgageProgram{ID: "salary", Salary: true},
+				Rate:     8,
+				Feasible: false,
+				Reason:   model.ReasonPaymentBelowInterest,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"solve":"term","program":{"id":"salary","salary":true,"military":false,"base":false},"rate":8,"feasible":false,"reason":"payment_below_interest"}}`,
+		},
+		{
+			name:           "unknown solve target",
+			requestBody:    `{"solve": "rate", "target_payment": 20000, "object_cost": 5000000, "program": {"salary": true}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "down payment requires months",
+			requestBody:    `{"solve": "down_payment", "target_payment": 20000, "object_cost": 5000000, "program": {"salary": true}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &MortgageController{
+				calc:  &MockCalculator{solve: tt.mockResult},
+				cache: &MockCache{},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/solve", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleSolve(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+// TestHandleCompare тестирует сравнение программ для одного набора параметров
+func TestHandleCompare(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockError      error
+		programs       []model.Program
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "successful comparison",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "rejected program is not an error",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240}`,
+			mockError:      calculator.ErrLoanTooLarge,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"params":{"object_cost":5000000,"initial_payment":1000000,"months":240},"offers":[],"by_overpayment":[],"rejected":[{"program_id":"base","program_name":"Base","reason":"loan sum exceeds the program maximum"}]}}`,
+		},
+		{
+			name:           "internal calculator error",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240}`,
+			mockError:      errors.New("database connection failed"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"internal server error"}`,
+		},
+		{
+			name:           "too many months (more than 600)",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 601}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "restricted program without borrower is offered",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240}`,
+			programs:       []model.Program{{ID: "military", Name: "Military", Rate: 6, Eligibility: &model.EligibilityRules{MilitaryService: true}}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"params":{"object_cost":5000000,"initial_payment":1000000,"months":240},"offers":[{"program_id":"military","program_name":"Military","calculation":{"params":{"object_cost":0,"initial_payment":0,"months":0},"program":{"salary":false,"military":false,"base":false},"aggregates":{"rate":0,"loan_sum":0,"monthly_payment":38601,"overpayment":5264144,"last_payment_date":"0001-01-01T00:00:00Z"}},"payment_rank":1,"overpayment_rank":1,"payment_difference":0,"overpayment_difference":0}],"by_overpayment":["military"]}}`,
+		},
+		{
+			name:           "invalid borrower",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240, "borrower": {"age": 12, "region": "77", "property_type": "secondary"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			programs := tt.programs
+			if programs == nil {
+				programs = []model.Program{{ID: "base", Name: "Base", Rate: 10}}
+			}
+			mockCalc := &MockCalculator{
+				result: &model.MortgageCalculation{
+					Aggregates: model.MortgageAggregates{MonthlyPayment: 38601, Overpayment: 5264144},
+				},
+				err:      tt.mockError,
+				programs: programs,
+			}
+			controller := &MortgageController{calc: mockCalc, cache: &MockCache{}}
+
+			req := httptest.NewRequest(http.MethodPost, "/compare", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleCompare(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+// TestHandleRefinance тестирует сравнение текущего кредита с рефинансированием
+func TestHandleRefinance(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockResult     *model.RefinanceResult
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful refinancing",
+			requestBody: `{"balance": 3000000, "rate": 16, "remaining_months": 120, "early_repayment_fee": 30000, "program": {"id": "base"}}`,
+			mockResult: &model.RefinanceResult{
+				Program:        model.MortgageProgram{ID: "base"},
+				MonthlySavings: 10000,
+				InterestSaved:  1200000,
+				Costs:          30000,
+				NetSavings:     1170000,
+				BreakEvenMonth: 3,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"program":{"id":"base","salary":false,"military":false,"base":false},"current":{"rate":0,"months":0,"monthly_payment":0,"overpayment":0,"last_payment_date":"0001-01-01T00:00:00Z","schedule":null},"offer":{"rate":0,"months":0,"monthly_payment":0,"overpayment":0,"last_payment_date":"0001-01-01T00:00:00Z","schedule":null},"monthly_savings":10000,"interest_saved":1200000,"costs":30000,"net_savings":1170000,"break_even_month":3}}`,
+		},
+		{
+			name:           "program not selected",
+			requestBody:    `{"balance": 3000000, "rate": 16, "remaining_months": 120, "program": {}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"choose program"}`,
+		},
+		{
+			name:           "missing remaining months",
+			requestBody:    `{"balance": 3000000, "rate": 16, "program": {"id": "base"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "business error from calculator",
+			requestBody:    `{"balance": 3000000, "rate": 16, "remaining_months": 120, "program": {"id": "unknown"}}`,
+			mockError:      calculator.ErrUnknownProgram,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"unknown program"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &MortgageController{
+				calc:  &MockCalculator{refinance: tt.mockResult, err: tt.mockError},
+				cache: &MockCache{},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/refinance", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleRefinance(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
+// TestHandleSimulate тестирует моделирование плавающей ставки
+func TestHandleSimulate(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockResult     *model.SimulationResult
+		mockError      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "successful simulation",
+			requestBody: `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240, "program": {"id": "base"}, "spread": 2, "model": "random_walk", "paths": 1000, "seed": 42, "volatility": 2}`,
+			mockResult: &model.SimulationResult{
+				Program:        model.MortgageProgram{ID: "base"},
+				Model:          model.SimulationRandomWalk,
+				Paths:          1000,
+				Seed:           42,
+				StartRate:      16,
+				LoanSum:        4000000,
+				MonthlyPayment: model.Percentiles{P5: 50000, P25: 55000, P50: 60000, P75: 65000, P95: 70000, Mean: 60000},
+				Overpayment:    model.Percentiles{P5: 8000000, P25: 9000000, P50: 10000000, P75: 11000000, P95: 12000000, Mean: 10000000},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"program":{"id":"base","salary":false,"military":false,"base":false},"model":"random_walk","paths":1000,"seed":42,"start_rate":16,"loan_sum":4000000,"monthly_payment":{"p5":50000,"p25":55000,"p50":60000,"p75":65000,"p95":70000,"mean":60000},"overpayment":{"p5":8000000,"p25":9000000,"p50":10000000,"p75":11000000,"p95":12000000,"mean":10000000}}}`,
+		},
+		{
+			name:           "mean reversion without speed",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240, "program": {"id": "base"}, "model": "mean_reverting", "paths": 1000, "volatility": 2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many paths",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240, "program": {"id": "base"}, "model": "random_walk", "paths": 10001, "volatility": 2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "cancelled simulation",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 240, "program": {"id": "base"}, "model": "random_walk", "paths": 1000, "volatility": 2}`,
+			mockError:      context.Canceled,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"internal server error"}`,
+		},
+		{
+			name:           "simulation timeout",
+			requestBody:    `{"object_cost": 5000000, "initial_payment": 1000000, "months": 600, "program": {"id": "base"}, "model": "random_walk", "paths": 1600, "volatility": 2}`,
+			mockError:      calculator.ErrSimulationTimeout, // Истек срок на расчет
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"simulation did not finish in time, reduce paths or months"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalc := &MockCalculator{simulation: tt.mockResult, err: tt.mockError}
+			controller := &MortgageController{calc: mockCalc, cache: &MockCache{}}
+
+			req := httptest.NewRequest(http.MethodPost, "/simulate", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleSimulate(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+
+			// Моделирование ограничено по времени
+			if tt.expectedStatus == http.StatusOK && !mockCalc.simulationDeadline {
+				t.Error("Expected simulation context with a deadline")
+			}
+		})
+	}
+}
+
+// TestHandleEligibility тестирует подбор программ по профилю заемщика
+func TestHandleEligibility(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "rejected program with reasons",
+			requestBody:    `{"age": 30, "children": 0, "region": "77", "property_type": "secondary"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":{"profile":{"age":30,"children":0,"military_service":false,"salary_project":false,"region":"77","property_type":"secondary"},"eligible":[{"id":"base","name":"Base","rate":10,"min_down_payment":0}],"rejected":[{"program_id":"family","program_name":"Family","reasons":["not_enough_children","property_type_not_eligible"]}]}}`,
+		},
+		{
+			name:           "underage borrower",
+			requestBody:    `{"age": 17, "region": "77", "property_type": "secondary"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown property type",
+			requestBody:    `{"age": 30, "region": "77", "property_type": "castle"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCalc := &MockCalculator{
+				programs: []model.Program{
+					{ID: "base", Name: "Base", Rate: 10},
+					{ID: "family", Name: "Family", Rate: 6, Eligibility: &model.EligibilityRules{
+						MinChildren:   1,
+						PropertyTypes: []string{model.PropertyNewBuilding},
+					}},
+				},
+			}
+			controller := &MortgageController{calc: mockCalc, cache: &MockCache{}}
+
+			req := httptest.NewRequest(http.MethodPost, "/eligibility", bytes.NewBufferString(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			controller.handleEligibility(rr, req)
+
+			checkResponse(t, rr, tt.expectedStatus, tt.expectedBody)
+		})
+	}
+}
+
 // ============================================================================
 // ВСПОМОГАТЕЛЬНЫЕ ФУНКЦИИ И ТЕСТЫ
 // ============================================================================
@@ -296,6 +722,33 @@ func normalizeJSON(s string) (string, error) {
 	return string(normalized), nil
 }
 
+// checkResponse проверяет статус код и, если задано, нормализованное тело ответа
+func checkResponse(t *testing.T, rr *httptest.ResponseRecorder, expectedStatus int, expectedBody string) {
+	t.Helper()
+
+	if rr.Code != expectedStatus {
+		t.Errorf("handler returned wrong status code: got %v want %v. Response body: %s", rr.Code, expectedStatus, rr.Body.String())
+	}
+
+	if expectedBody == "" {
+		return
+	}
+
+	expectedNormalized, err := normalizeJSON(expectedBody)
+	if err != nil {
+		t.Fatalf("Failed to normalize expected JSON: %v", err)
+	}
+
+	actualNormalized, err := normalizeJSON(rr.Body.String())
+	if err != nil {
+		t.Fatalf("Failed to normalize actual JSON: %v", err)
+	}
+
+	if actualNormalized != expectedNormalized {
+		t.Errorf("handler returned unexpected body:\ngot:  %v\nwant: %v", actualNormalized, expectedNormalized)
+	}
+}
+
 // TestValidateProgram тестирует функцию валидации ипотечных программ
 func TestValidateProgram(t *testing.T) {
 	tests := []struct {
@@ -318,6 +771,16 @@ func TestValidateProgram(t *testing.T) {
 			program: model.MortgageProgram{Salary: true},
 			wantErr: false,
 		},
+		{
+			name:    "valid program by id",
+			program: model.MortgageProgram{ID: "family"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid program - id and flag set",
+			program: model.MortgageProgram{ID: "family", Base: true}, // ID и флаг одновременно
+			wantErr: true,
+		},
 		{
 			name:    "invalid program - no flags set",
 			program: model.MortgageProgram{}, // Все флаги false
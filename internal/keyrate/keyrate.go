@@ -0,0 +1,92 @@
+package keyrate
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// History is the key rate history, each rate is in effect from its date
+// until the next change. Forecast entries may be dated in the future.
+type History struct {
+	changes []change
+}
+
+type change struct {
+	date time.Time
+	rate float64
+}
+
+// NewHistory returns a history from rates keyed by the date they take effect.
+func NewHistory(rates map[time.Time]float64) *History {
+	h := &History{changes: make([]change, 0, len(rates))}
+	for date, rate := range rates {
+		h.changes = append(h.changes, change{date: date, rate: rate})
+	}
+	sort.Slice(h.changes, func(i, j int) bool {
+		return h.changes[i].date.Before(h.changes[j].date)
+	})
+	return h
+}
+
+// Load reads a CSV file with "date,rate" rows, dates are YYYY-MM-DD and
+// the first row is a header.
+func Load(path string) (*History, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open key rate file: %w", err)
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read parses the key rate CSV from r, see Load.
+func Read(r io.Reader) (*History, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read key rates: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, errors.New("key rate file has no rates")
+	}
+
+	rates := make(map[time.Time]float64, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("key rate row %d: expected date and rate", i+2)
+		}
+
+		date, err := time.Parse(dateLayout, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("key rate row %d: %w", i+2, err)
+		}
+		rate, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("key rate row %d: %w", i+2, err)
+		}
+
+		rates[date] = rate
+	}
+
+	return NewHistory(rates), nil
+}
+
+// RateOn returns the key rate in effect on the date. Dates after the last
+// change get the last known rate, dates before the history are not known.
+func (h *History) RateOn(date time.Time) (float64, bool) {
+	i := sort.Search(len(h.changes), func(i int) bool {
+		return h.changes[i].date.After(date)
+	})
+	if i == 0 {
+		return 0, false
+	}
+	return h.changes[i-1].rate, true
+}
@@ -0,0 +1,54 @@
+package keyrate
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory_RateOn(t *testing.T) {
+	history, err := Read(strings.NewReader("date,rate\n2024-10-28,21\n2024-07-29,18\n2025-06-09,20\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		want   float64
+		wantOK bool
+	}{
+		{"before history", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 0, false},
+		{"on the change date", time.Date(2024, 7, 29, 0, 0, 0, 0, time.UTC), 18, true},
+		{"between changes", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 21, true},
+		{"after the last change", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), 20, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := history.RateOn(tt.date)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("RateOn() = %f, %v; want %f, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRead_InvalidRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"header only", "date,rate\n"},
+		{"invalid date", "date,rate\n29.07.2024,18\n"},
+		{"invalid rate", "date,rate\n2024-07-29,high\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(tt.input)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
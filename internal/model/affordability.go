@@ -0,0 +1,40 @@
+package model
+
+// AffordabilityRequest asks for the most expensive property a borrower can
+// buy with the given monthly budget and savings. With MonthlyIncome the
+// budget is also capped by the maximum debt load including DebtPayments.
+type AffordabilityRequest struct {
+	MaxMonthlyPayment float64         `json:"max_monthly_payment" validate:"required_without=MonthlyIncome,min=0"`
+	MonthlyIncome     float64         `json:"monthly_income,omitempty" validate:"omitempty,gt=0"`
+	DebtPayments      float64         `json:"debt_payments,omitempty" validate:"min=0"`
+	DownPayment       float64         `json:"down_payment" validate:"min=0"`
+	Months            int             `json:"months" validate:"required,min=1,max=600"`
+	Program           MortgageProgram `json:"program" validate:"required"`
+}
+
+// AffordabilityResult is the maximum loan and property cost. LimitedBy names
+// the constraint that capped the loan: the monthly payment, the debt load,
+// the down payment share of the program, the program loan limit or the rate
+// grid, when a larger loan moves to a more expensive tier. RateTier is the
+// grid cell of the loan.
+type AffordabilityResult struct {
+	Program        MortgageProgram `json:"program"`
+	Rate           float64         `json:"rate"`
+	RateTier       *RateTier       `json:"rate_tier,omitempty"`
+	Months         int             `json:"months"`
+	DownPayment    float64         `json:"down_payment"`
+	MaxLoan        float64         `json:"max_loan"`
+	MaxObjectCost  float64         `json:"max_object_cost"`
+	MonthlyPayment float64         `json:"monthly_payment"`
+	LimitedBy      string          `json:"limited_by"`
+	DebtLoad       *DebtLoad       `json:"debt_load,omitempty"`
+}
+
+// Constraints reported in AffordabilityResult.LimitedBy.
+const (
+	LimitedByPayment     = "payment"
+	LimitedByDownPayment = "down_payment"
+	LimitedByMaxLoan     = "max_loan"
+	LimitedByDebtLoad    = "debt_load"
+	LimitedByRateTier    = "rate_tier"
+)
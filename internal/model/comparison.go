@@ -0,0 +1,40 @@
+package model
+
+// CompareRequest is the params to compare programs with. Programs with
+// eligibility rules are only offered to the given borrower.
+type CompareRequest struct {
+	MortgageParams
+	Borrower *BorrowerProfile `json:"borrower,omitempty"`
+}
+
+// Comparison is the calculation of one set of params for every catalog
+// program. Offers are ranked by monthly payment, ByOverpayment lists program
+// IDs ranked by overpayment, Rejected are the programs the params do not
+// qualify for.
+type Comparison struct {
+	Params        MortgageParams    `json:"params"`
+	Offers        []ProgramOffer    `json:"offers"`
+	ByOverpayment []string          `json:"by_overpayment"`
+	Rejected      []RejectedProgram `json:"rejected,omitempty"`
+}
+
+// ProgramOffer is the calculation for one program. Differences are measured
+// against the best offer of the corresponding ranking.
+type ProgramOffer struct {
+	ProgramID             string               `json:"program_id"`
+	ProgramName           string               `json:"program_name"`
+	Calculation           *MortgageCalculation `json:"calculation"`
+	PaymentRank           int                  `json:"payment_rank"`
+	OverpaymentRank       int                  `json:"overpayment_rank"`
+	PaymentDifference     float64              `json:"payment_difference"`
+	OverpaymentDifference float64              `json:"overpayment_difference"`
+}
+
+// RejectedProgram is a program the params do not qualify for. Reasons are
+// the eligibility rules the borrower fails, see IneligibleProgram.
+type RejectedProgram struct {
+	ProgramID   string   `json:"program_id"`
+	ProgramName string   `json:"program_name"`
+	Reason      string   `json:"reason"`
+	Reasons     []string `json:"reasons,omitempty"`
+}
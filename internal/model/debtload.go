@@ -0,0 +1,27 @@
+package model
+
+// DebtLoadLimits are the debt-to-income thresholds in percent of the monthly
+// income. Above Warning the debt load is high, above Max the loan is not
+// approvable.
+type DebtLoadLimits struct {
+	Warning float64 `json:"warning" mapstructure:"warning"`
+	Max     float64 `json:"max" mapstructure:"max"`
+}
+
+// DebtLoad is the debt-to-income ratio (PDN) of the borrower with the
+// mortgage payment: all monthly debt payments in percent of the income.
+type DebtLoad struct {
+	MonthlyIncome   float64 `json:"monthly_income"`
+	DebtPayments    float64 `json:"debt_payments"`
+	MortgagePayment float64 `json:"mortgage_payment"`
+	Ratio           float64 `json:"ratio"`
+	Level           string  `json:"level"`
+	ExceedsLimit    bool    `json:"exceeds_limit"`
+}
+
+// Levels of DebtLoad.
+const (
+	DebtLoadNormal    = "normal"
+	DebtLoadHigh      = "high"
+	DebtLoadExcessive = "excessive"
+)
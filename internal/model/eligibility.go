@@ -0,0 +1,56 @@
+package model
+
+// BorrowerProfile describes the borrower for the program eligibility rules.
+type BorrowerProfile struct {
+	Age             int    `json:"age" validate:"required,min=18,max=100"`
+	Children        int    `json:"children" validate:"min=0"`
+	MilitaryService bool   `json:"military_service"`
+	SalaryProject   bool   `json:"salary_project"`
+	Region          string `json:"region" validate:"required"`
+	PropertyType    string `json:"property_type" validate:"required,oneof=new_building secondary house"`
+}
+
+// Property types of BorrowerProfile.
+const (
+	PropertyNewBuilding = "new_building"
+	PropertySecondary   = "secondary"
+	PropertyHouse       = "house"
+)
+
+// EligibilityRules restrict a program to some borrowers. Zero ages and
+// children and empty lists are not applied.
+type EligibilityRules struct {
+	MinAge          int      `json:"min_age,omitempty" mapstructure:"min_age"`
+	MaxAge          int      `json:"max_age,omitempty" mapstructure:"max_age"`
+	MinChildren     int      `json:"min_children,omitempty" mapstructure:"min_children"`
+	MilitaryService bool     `json:"military_service,omitempty" mapstructure:"military_service"`
+	SalaryProject   bool     `json:"salary_project,omitempty" mapstructure:"salary_project"`
+	Regions         []string `json:"regions,omitempty" mapstructure:"regions"`
+	PropertyTypes   []string `json:"property_types,omitempty" mapstructure:"property_types"`
+}
+
+// Eligibility lists the catalog programs the borrower qualifies for and the
+// reasons of every rejection.
+type Eligibility struct {
+	Profile  BorrowerProfile     `json:"profile"`
+	Eligible []Program           `json:"eligible"`
+	Rejected []IneligibleProgram `json:"rejected,omitempty"`
+}
+
+// IneligibleProgram is a program with all the rules the borrower fails.
+type IneligibleProgram struct {
+	ProgramID   string   `json:"program_id"`
+	ProgramName string   `json:"program_name"`
+	Reasons     []string `json:"reasons"`
+}
+
+// Rejection reasons of IneligibleProgram.
+const (
+	ReasonAgeBelowMinimum         = "age_below_minimum"
+	ReasonAgeAboveMaximum         = "age_above_maximum"
+	ReasonNotEnoughChildren       = "not_enough_children"
+	ReasonMilitaryServiceRequired = "military_service_required"
+	ReasonSalaryProjectRequired   = "salary_project_required"
+	ReasonRegionNotEligible       = "region_not_eligible"
+	ReasonPropertyTypeNotEligible = "property_type_not_eligible"
+)
@@ -0,0 +1,46 @@
+package model
+
+// Program is a mortgage program from the catalog. MinDownPayment is the
+// minimum share of the object cost (0.2 is 20%), zero limits are not applied.
+// LifeInsuranceSurcharge is added to the rate when life insurance is refused.
+// Eligibility restricts the program to some borrowers, nil allows everyone.
+// RateGrid adjusts the rate by loan-to-value and term, nil keeps it flat.
+// A loan above SubsidyLimit is combined: the excess is issued at ExcessRate.
+type Program struct {
+	ID                     string            `json:"id" mapstructure:"id"`
+	Name                   string            `json:"name" mapstructure:"name"`
+	Rate                   float64           `json:"rate" mapstructure:"rate"`
+	MinDownPayment         float64           `json:"min_down_payment" mapstructure:"min_down_payment"`
+	MaxMonths              int               `json:"max_months,omitempty" mapstructure:"max_months"`
+	MaxLoan                float64           `json:"max_loan,omitempty" mapstructure:"max_loan"`
+	LifeInsuranceSurcharge float64           `json:"life_insurance_surcharge,omitempty" mapstructure:"life_insurance_surcharge"`
+	Eligibility            *EligibilityRules `json:"eligibility,omitempty" mapstructure:"eligibility"`
+	RateGrid               *RateGrid         `json:"rate_grid,omitempty" mapstructure:"rate_grid"`
+	SubsidyLimit           float64           `json:"subsidy_limit,omitempty" mapstructure:"subsidy_limit"`
+	ExcessRate             float64           `json:"excess_rate,omitempty" mapstructure:"excess_rate"`
+}
+
+// RateGrid adjusts the program rate by loan-to-value and term. LTVBands
+// (shares of the object cost) and TermBands (months) are ascending upper
+// bounds of the bands, Adjustments[i][j] is added to the rate for the i-th
+// LTV band and the j-th term band, negative values are discounts.
+type RateGrid struct {
+	LTVBands    []float64   `json:"ltv_bands" mapstructure:"ltv_bands"`
+	TermBands   []int       `json:"term_bands" mapstructure:"term_bands"`
+	Adjustments [][]float64 `json:"adjustments" mapstructure:"adjustments"`
+}
+
+// RateTier is the cell of the program rate grid applied to a loan.
+type RateTier struct {
+	LTV        float64 `json:"ltv"`
+	MaxLTV     float64 `json:"max_ltv"`
+	MaxMonths  int     `json:"max_months"`
+	Adjustment float64 `json:"adjustment"`
+}
+
+// IDs of the programs selected by the legacy bool flags of MortgageProgram.
+const (
+	ProgramSalary   = "salary"
+	ProgramMilitary = "military"
+	ProgramBase     = "base"
+)
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// RefinanceRequest describes the existing loan and the catalog program to
+// refinance it with. The new loan repays Balance over Months, the remaining
+// term by default. EarlyRepaymentFee of the current lender and the one-time
+// Costs of the new loan are paid by the borrower and recovered by savings.
+// ObjectCost is the property value, required by programs with a rate grid.
+type RefinanceRequest struct {
+	Balance           float64         `json:"balance" validate:"required,gt=0"`
+	Rate              float64         `json:"rate" validate:"min=0"`
+	RemainingMonths   int             `json:"remaining_months" validate:"required,min=1,max=600"`
+	EarlyRepaymentFee float64         `json:"early_repayment_fee" validate:"min=0"`
+	Costs             []Fee           `json:"costs,omitempty" validate:"omitempty,dive"`
+	Program           MortgageProgram `json:"program" validate:"required"`
+	Months            int             `json:"months,omitempty" validate:"omitempty,min=1,max=600"`
+	IssueDate         *Date           `json:"issue_date,omitempty"`
+	ObjectCost        float64         `json:"object_cost,omitempty" validate:"omitempty,gt=0"`
+}
+
+// RefinanceResult compares the current loan with the refinanced one.
+// BreakEvenMonth is the first month when the accumulated payment savings
+// cover the costs, zero when they never do. NetSavings are all the payment
+// savings less the costs. RateTier is the grid cell of the new loan.
+type RefinanceResult struct {
+	Program        MortgageProgram `json:"program"`
+	Current        RefinanceLoan   `json:"current"`
+	Offer          RefinanceLoan   `json:"offer"`
+	RateTier       *RateTier       `json:"rate_tier,omitempty"`
+	MonthlySavings float64         `json:"monthly_savings"`
+	InterestSaved  float64         `json:"interest_saved"`
+	Costs          float64         `json:"costs"`
+	NetSavings     float64         `json:"net_savings"`
+	BreakEvenMonth int             `json:"break_even_month"`
+}
+
+// RefinanceLoan is one side of the refinancing comparison with its schedule.
+// Tranches are set for an offer above the program subsidy limit.
+type RefinanceLoan struct {
+	Rate            float64          `json:"rate"`
+	Months          int              `json:"months"`
+	MonthlyPayment  float64          `json:"monthly_payment"`
+	Overpayment     float64          `json:"overpayment"`
+	LastPaymentDate time.Time        `json:"last_payment_date"`
+	Schedule        []SchedulePeriod `json:"schedule"`
+	Tranches        []Tranche        `json:"tranches,omitempty"`
+}
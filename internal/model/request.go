@@ -1,14 +1,191 @@
 package model
 
 type MortgageRequest struct {
-	ObjectCost     float64         `json:"object_cost" validate:"required,min=0"`
-	InitialPayment float64         `json:"initial_payment" validate:"required,min=0"`
-	Months         int             `json:"months" validate:"required,min=1,max=600"`
-	Program        MortgageProgram `json:"program" validate:"required"`
+	ObjectCost     float64          `json:"object_cost" validate:"required,min=0"`
+	InitialPayment float64          `json:"initial_payment" validate:"required,min=0"`
+	Months         int              `json:"months" validate:"required,min=1,max=600"`
+	Program        MortgageProgram  `json:"program" validate:"required"`
+	RepaymentType  string           `json:"repayment_type" validate:"omitempty,oneof=annuity differentiated"`
+	Prepayments    []Prepayment     `json:"prepayments,omitempty" validate:"omitempty,dive"`
+	Fees           *CreditFees      `json:"fees,omitempty"`
+	Insurance      *Insurance       `json:"insurance,omitempty"`
+	RateTimeline   []RateChange     `json:"rate_timeline,omitempty" validate:"omitempty,dive"`
+	Grace          *GracePeriod     `json:"grace,omitempty"`
+	IssueDate      *Date            `json:"issue_date,omitempty"`
+	PaymentDay     int              `json:"payment_day,omitempty" validate:"omitempty,min=1,max=31"`
+	DayCount       string           `json:"day_count,omitempty" validate:"omitempty,oneof=30/360 actual/365 actual/actual"`
+	Subsidies      []Subsidy        `json:"subsidies,omitempty" validate:"omitempty,dive"`
+	Borrower       *BorrowerProfile `json:"borrower,omitempty"`
+	MonthlyIncome  float64          `json:"monthly_income,omitempty" validate:"omitempty,gt=0"`
+	DebtPayments   float64          `json:"debt_payments,omitempty" validate:"min=0"`
+	BuyDown        *BuyDown         `json:"buy_down,omitempty"`
+	Disbursements  []Disbursement   `json:"disbursements,omitempty" validate:"omitempty,dive"`
+	Holiday        *PaymentHoliday  `json:"holiday,omitempty"`
+	Schedule       bool             `json:"schedule"`
 }
 
+// GracePeriod is the initial period with interest-only payments, or with a
+// reduced fixed Payment when it is set. The loan is then amortized over the
+// remaining term.
+type GracePeriod struct {
+	Months  int     `json:"months" validate:"required,min=1,max=599"`
+	Payment float64 `json:"payment" validate:"min=0"`
+}
+
+// BuyDown is a rate subsidized by the developer for the first Months of the
+// loan, zero months cover the whole term. The developer pays the bank
+// Commission, a share of the object cost that is included into the price.
+type BuyDown struct {
+	Rate       float64 `json:"rate" validate:"min=0"`
+	Months     int     `json:"months,omitempty" validate:"omitempty,min=1,max=600"`
+	Commission float64 `json:"commission" validate:"min=0,lt=1"`
+}
+
+// Disbursement is a construction tranche released with the payment of the
+// given Month, month 0 is the issue. Interest is paid only on the disbursed
+// amount until the last tranche, the loan is then amortized.
+type Disbursement struct {
+	Month  int     `json:"month" validate:"min=0,max=599"`
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// PaymentHoliday suspends or reduces to Payment the payments of up to six
+// months starting with FromMonth. The deferred parts of the payments are
+// repaid after the last payment without extra interest.
+type PaymentHoliday struct {
+	FromMonth int     `json:"from_month" validate:"required,min=1,max=600"`
+	Months    int     `json:"months" validate:"required,min=1,max=6"`
+	Mode      string  `json:"mode" validate:"required,oneof=suspend reduce"`
+	Payment   float64 `json:"payment,omitempty" validate:"required_if=Mode reduce,min=0"`
+}
+
+// Modes of PaymentHoliday.
+const (
+	HolidaySuspend = "suspend"
+	HolidayReduce  = "reduce"
+)
+
+// RateChange sets the rate from the given month onward: either a fixed Rate
+// or a floating key rate plus Spread. A floating rate follows the key rate
+// history and is reset whenever the key rate changes.
+type RateChange struct {
+	FromMonth int      `json:"from_month" validate:"required,min=1,max=600"`
+	Rate      *float64 `json:"rate,omitempty" validate:"omitempty,min=0"`
+	Spread    *float64 `json:"spread,omitempty"`
+}
+
+// Insurance holds yearly tariffs in percent of the outstanding debt at the
+// start of each insurance year. RefuseLife drops the life policy, the rate
+// is then raised by the program life insurance surcharge.
+type Insurance struct {
+	PropertyRate float64 `json:"property_rate" validate:"min=0,max=100"`
+	LifeRate     float64 `json:"life_rate" validate:"min=0,max=100"`
+	TitleRate    float64 `json:"title_rate" validate:"min=0,max=100"`
+	RefuseLife   bool    `json:"refuse_life"`
+}
+
+// CreditFees are the borrower costs besides interest that count towards the
+// full cost of credit: one-time fees paid at issue and recurring costs.
+type CreditFees struct {
+	OneTime   []Fee          `json:"one_time,omitempty" validate:"omitempty,dive"`
+	Recurring []RecurringFee `json:"recurring,omitempty" validate:"omitempty,dive"`
+}
+
+// Fee is a one-time cost such as appraisal, commission or registration.
+type Fee struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// RecurringFee is paid with every payment (monthly) or at issue and on every
+// loan anniversary while the loan is outstanding (annual).
+type RecurringFee struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	Period string  `json:"period" validate:"required,oneof=monthly annual"`
+}
+
+// Periods of RecurringFee.
+const (
+	FeeMonthly = "monthly"
+	FeeAnnual  = "annual"
+)
+
+// Subsidy is maternity capital or a regional payment put towards the loan.
+// Subsidies applied to the down payment count towards the minimum initial
+// payment, subsidies applied to the principal are early repayments made on
+// the given Month or Date with the Strategy (reduce_term by default).
+type Subsidy struct {
+	Type      string  `json:"type" validate:"required,oneof=maternity_capital regional"`
+	Amount    float64 `json:"amount" validate:"required,gt=0"`
+	AppliedTo string  `json:"applied_to" validate:"required,oneof=down_payment principal"`
+	Month     int     `json:"month,omitempty" validate:"omitempty,min=1,max=600"`
+	Date      *Date   `json:"date,omitempty"`
+	Strategy  string  `json:"strategy,omitempty" validate:"omitempty,oneof=reduce_term reduce_payment"`
+}
+
+// Types and application points of Subsidy.
+const (
+	SubsidyMaternityCapital = "maternity_capital"
+	SubsidyRegional         = "regional"
+
+	SubsidyToDownPayment = "down_payment"
+	SubsidyToPrincipal   = "principal"
+)
+
+// Prepayment is an early repayment made on top of the regular payment.
+// It is scheduled either by month number or by date, EveryMonths makes it
+// recurring and Count limits the number of repetitions (0 - until the end).
+type Prepayment struct {
+	Month       int     `json:"month,omitempty" validate:"omitempty,min=1,max=600"`
+	Date        *Date   `json:"date,omitempty"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	Strategy    string  `json:"strategy" validate:"required,oneof=reduce_term reduce_payment"`
+	EveryMonths int     `json:"every_months,omitempty" validate:"omitempty,min=1"`
+	Count       int     `json:"count,omitempty" validate:"omitempty,min=1"`
+}
+
+// Prepayment strategies.
+const (
+	PrepaymentReduceTerm    = "reduce_term"
+	PrepaymentReducePayment = "reduce_payment"
+)
+
+// Repayment types supported by the calculator.
+const (
+	RepaymentAnnuity        = "annuity"
+	RepaymentDifferentiated = "differentiated"
+)
+
+// Day count conventions for interest accrual. 30/360 is the default and
+// accrues a twelfth of the annual rate regardless of the payment dates.
+const (
+	DayCount30360        = "30/360"
+	DayCountActual365    = "actual/365"
+	DayCountActualActual = "actual/actual"
+)
+
+// MortgageProgram selects a catalog program by ID. The bool flags are kept
+// for backward compatibility and select the program with the same ID.
 type MortgageProgram struct {
-	Salary   bool `json:"salary"`
-	Military bool `json:"military"`
-	Base     bool `json:"base"`
+	ID       string `json:"id,omitempty"`
+	Salary   bool   `json:"salary"`
+	Military bool   `json:"military"`
+	Base     bool   `json:"base"`
+}
+
+// ProgramID returns the ID of the selected program.
+func (p MortgageProgram) ProgramID() string {
+	switch {
+	case p.ID != "":
+		return p.ID
+	case p.Salary:
+		return ProgramSalary
+	case p.Military:
+		return ProgramMilitary
+	case p.Base:
+		return ProgramBase
+	default:
+		return ""
+	}
 }
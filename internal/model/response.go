@@ -12,18 +12,155 @@ type MortgageCalculation struct {
 	Params     MortgageParams     `json:"params"`
 	Program    MortgageProgram    `json:"program"`
 	Aggregates MortgageAggregates `json:"aggregates"`
+	Schedule   []SchedulePeriod   `json:"schedule,omitempty"`
+	// RateSegments are filled for loans with a rate timeline.
+	RateSegments []RateSegment `json:"rate_segments,omitempty"`
+	// EarlyRepayment is filled when the request has prepayments,
+	// the schedule is then the recalculated one.
+	EarlyRepayment *EarlyRepayment `json:"early_repayment,omitempty"`
+	// DebtLoad is filled when the request has the borrower income.
+	DebtLoad *DebtLoad `json:"debt_load,omitempty"`
+	// RateTier is filled for programs with a rate grid.
+	RateTier *RateTier `json:"rate_tier,omitempty"`
+	// Tranches are filled for loans above the program subsidy limit, the
+	// aggregates and the schedule are then the combined ones.
+	Tranches []Tranche `json:"tranches,omitempty"`
+	// BuyDown is filled when the request has a developer buy-down.
+	BuyDown *BuyDownComparison `json:"buy_down,omitempty"`
+	// CashFlows are filled for construction loans disbursed in tranches.
+	CashFlows []CashFlow `json:"cash_flows,omitempty"`
+	// Holiday is filled when the request has a payment holiday, the schedule
+	// is then the one with the holiday.
+	Holiday *HolidayResult `json:"holiday,omitempty"`
+	// EligibilityUnverified is set for programs with eligibility rules
+	// calculated without a borrower profile.
+	EligibilityUnverified bool `json:"eligibility_unverified,omitempty"`
 }
 
 type MortgageParams struct {
-	ObjectCost     float64 `json:"object_cost"`
-	InitialPayment float64 `json:"initial_payment"`
-	Months         int     `json:"months"`
+	ObjectCost     float64 `json:"object_cost" validate:"required,min=0"`
+	InitialPayment float64 `json:"initial_payment" validate:"required,min=0"`
+	Months         int     `json:"months" validate:"required,min=1,max=600"`
 }
 
 type MortgageAggregates struct {
-	Rate            float64   `json:"rate"`
-	LoanSum         float64   `json:"loan_sum"`
+	Rate             float64   `json:"rate"`
+	LoanSum          float64   `json:"loan_sum"`
+	MonthlyPayment   float64   `json:"monthly_payment"`
+	Overpayment      float64   `json:"overpayment"`
+	LastPaymentDate  time.Time `json:"last_payment_date"`
+	EffectiveRate    float64   `json:"effective_rate,omitempty"`
+	FullCostRate     float64   `json:"full_cost_rate,omitempty"`
+	FeesTotal        float64   `json:"fees_total,omitempty"`
+	InsuranceCost    float64   `json:"insurance_cost,omitempty"`
+	RateSurcharge    float64   `json:"rate_surcharge,omitempty"`
+	GracePayment     float64   `json:"grace_payment,omitempty"`
+	PostGracePayment float64   `json:"post_grace_payment,omitempty"`
+	RepaymentType    string    `json:"repayment_type,omitempty"`
+	DayCount         string    `json:"day_count,omitempty"`
+	// CalendarWarning is set when payment dates are outside the holiday
+	// calendar and are moved over weekends only
+	CalendarWarning string `json:"calendar_warning,omitempty"`
+	// DownPaymentSubsidies is the part of the down payment paid by subsidies
+	DownPaymentSubsidies float64 `json:"down_payment_subsidies,omitempty"`
+	FirstPayment         float64 `json:"first_payment,omitempty"`
+	LastPayment          float64 `json:"last_payment,omitempty"`
+}
+
+// SchedulePeriod is one row of the amortization schedule. Prepayment is the
+// early repayment made right after the regular payment.
+type SchedulePeriod struct {
+	Number     int       `json:"number"`
+	Date       time.Time `json:"date"`
+	Payment    float64   `json:"payment"`
+	Interest   float64   `json:"interest"`
+	Principal  float64   `json:"principal"`
+	Prepayment float64   `json:"prepayment,omitempty"`
+	// Disbursement is the construction tranche released after the payment.
+	Disbursement float64 `json:"disbursement,omitempty"`
+	Balance      float64 `json:"balance"`
+}
+
+// RateSegment is a run of periods with the same rate and its payment.
+type RateSegment struct {
+	FromMonth int     `json:"from_month"`
+	ToMonth   int     `json:"to_month"`
+	Rate      float64 `json:"rate"`
+	Payment   float64 `json:"payment"`
+}
+
+// EarlyRepayment compares the loan with prepayments against the baseline.
+type EarlyRepayment struct {
+	Months          int       `json:"months"`
 	MonthlyPayment  float64   `json:"monthly_payment"`
+	TotalPrepaid    float64   `json:"total_prepaid"`
 	Overpayment     float64   `json:"overpayment"`
+	InterestSaved   float64   `json:"interest_saved"`
 	LastPaymentDate time.Time `json:"last_payment_date"`
 }
+
+// Tranche is a part of a combined loan repaid with its own rate.
+type Tranche struct {
+	Type           string  `json:"type"`
+	LoanSum        float64 `json:"loan_sum"`
+	Rate           float64 `json:"rate"`
+	MonthlyPayment float64 `json:"monthly_payment"`
+	Overpayment    float64 `json:"overpayment"`
+}
+
+// Types of Tranche.
+const (
+	TrancheSubsidized = "subsidized"
+	TrancheExcess     = "excess"
+)
+
+// BuyDownComparison compares the developer buy-down with buying at the price
+// without the commission at the regular program rate with the same down
+// payment. Total costs are the down payment plus all the loan payments,
+// FullCostRate is the monthly rate of the buy-down payments against the
+// discounted loan times 12, disclosed as MortgageAggregates.FullCostRate,
+// Savings are positive when the buy-down is cheaper.
+type BuyDownComparison struct {
+	DiscountedPrice     float64 `json:"discounted_price"`
+	BuyDownPayment      float64 `json:"buy_down_payment"`
+	PaymentAfterBuyDown float64 `json:"payment_after_buy_down,omitempty"`
+	BuyDownTotalCost    float64 `json:"buy_down_total_cost"`
+	FullCostRate        float64 `json:"full_cost_rate"`
+	RegularLoanSum      float64 `json:"regular_loan_sum"`
+	RegularPayment      float64 `json:"regular_payment"`
+	RegularTotalCost    float64 `json:"regular_total_cost"`
+	Savings             float64 `json:"savings"`
+	Better              string  `json:"better"`
+}
+
+// Options of BuyDownComparison.Better.
+const (
+	BetterBuyDown  = "buy_down"
+	BetterDiscount = "discount"
+)
+
+// CashFlow is a cash flow of a construction loan: the tranche released to
+// the escrow account and the payment made to the bank, number 0 is the issue.
+type CashFlow struct {
+	Number       int       `json:"number"`
+	Date         time.Time `json:"date"`
+	Disbursement float64   `json:"disbursement"`
+	Payment      float64   `json:"payment"`
+	Balance      float64   `json:"balance"`
+}
+
+// HolidayResult shows the effect of a payment holiday: the deferred interest
+// and principal repaid after the baseline term, the term extension in months
+// and the new last payment date against the baseline one. Deferred amounts
+// bear no interest, so the overpayment stays the baseline one.
+type HolidayResult struct {
+	FromMonth               int       `json:"from_month"`
+	Months                  int       `json:"months"`
+	Mode                    string    `json:"mode"`
+	DeferredInterest        float64   `json:"deferred_interest"`
+	DeferredPrincipal       float64   `json:"deferred_principal"`
+	TermExtension           int       `json:"term_extension"`
+	Overpayment             float64   `json:"overpayment"`
+	BaselineLastPaymentDate time.Time `json:"baseline_last_payment_date"`
+	LastPaymentDate         time.Time `json:"last_payment_date"`
+}
@@ -0,0 +1,51 @@
+package model
+
+// SimulationRequest runs Paths key rate paths for a floating-rate loan at
+// the key rate plus Spread. The key rate starts at StartRate, the current
+// key rate by default, and moves monthly as a random walk with the annual
+// Volatility in percentage points, or reverts to MeanRate (the start rate by
+// default) with the annual speed Reversion. Equal seeds give equal results.
+// Paths times Months is limited to 1000000.
+type SimulationRequest struct {
+	ObjectCost     float64         `json:"object_cost" validate:"required,gt=0"`
+	InitialPayment float64         `json:"initial_payment" validate:"min=0"`
+	Months         int             `json:"months" validate:"required,min=1,max=600"`
+	Program        MortgageProgram `json:"program" validate:"required"`
+	Spread         float64         `json:"spread"`
+	Model          string          `json:"model" validate:"required,oneof=random_walk mean_reverting"`
+	Paths          int             `json:"paths" validate:"required,min=1,max=10000"`
+	Seed           uint64          `json:"seed"`
+	Volatility     float64         `json:"volatility" validate:"min=0"`
+	StartRate      *float64        `json:"start_rate,omitempty" validate:"omitempty,min=0"`
+	MeanRate       *float64        `json:"mean_rate,omitempty" validate:"omitempty,min=0"`
+	Reversion      float64         `json:"reversion" validate:"required_if=Model mean_reverting,min=0"`
+}
+
+// Rate models of SimulationRequest.
+const (
+	SimulationRandomWalk    = "random_walk"
+	SimulationMeanReverting = "mean_reverting"
+)
+
+// SimulationResult is the distribution of the loan outcomes over the rate
+// paths. MonthlyPayment is the highest regular payment of every path.
+type SimulationResult struct {
+	Program        MortgageProgram `json:"program"`
+	Model          string          `json:"model"`
+	Paths          int             `json:"paths"`
+	Seed           uint64          `json:"seed"`
+	StartRate      float64         `json:"start_rate"`
+	LoanSum        float64         `json:"loan_sum"`
+	MonthlyPayment Percentiles     `json:"monthly_payment"`
+	Overpayment    Percentiles     `json:"overpayment"`
+}
+
+// Percentiles of a simulated value.
+type Percentiles struct {
+	P5   float64 `json:"p5"`
+	P25  float64 `json:"p25"`
+	P50  float64 `json:"p50"`
+	P75  float64 `json:"p75"`
+	P95  float64 `json:"p95"`
+	Mean float64 `json:"mean"`
+}
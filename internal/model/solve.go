@@ -0,0 +1,42 @@
+package model
+
+// SolveRequest asks for the term (solve=term) or the down payment
+// (solve=down_payment) that gives the target monthly payment.
+type SolveRequest struct {
+	Solve          string          `json:"solve" validate:"required,oneof=term down_payment"`
+	TargetPayment  float64         `json:"target_payment" validate:"required,gt=0"`
+	ObjectCost     float64         `json:"object_cost" validate:"required,gt=0"`
+	InitialPayment float64         `json:"initial_payment" validate:"min=0"`
+	Months         int             `json:"months" validate:"required_if=Solve down_payment,omitempty,min=1,max=600"`
+	Program        MortgageProgram `json:"program" validate:"required"`
+}
+
+// Values of SolveRequest.Solve.
+const (
+	SolveTerm        = "term"
+	SolveDownPayment = "down_payment"
+)
+
+// SolveResult is the solved term or down payment. When the target cannot be
+// reached Feasible is false and Reason explains why. Rate and RateTier are
+// those of the solved loan for programs with a rate grid.
+type SolveResult struct {
+	Solve          string          `json:"solve"`
+	Program        MortgageProgram `json:"program"`
+	Rate           float64         `json:"rate"`
+	RateTier       *RateTier       `json:"rate_tier,omitempty"`
+	Feasible       bool            `json:"feasible"`
+	Reason         string          `json:"reason,omitempty"`
+	Details        string          `json:"details,omitempty"`
+	Months         int             `json:"months,omitempty"`
+	InitialPayment float64         `json:"initial_payment,omitempty"`
+	LoanSum        float64         `json:"loan_sum,omitempty"`
+	MonthlyPayment float64         `json:"monthly_payment,omitempty"`
+}
+
+// Reasons reported in SolveResult.Reason.
+const (
+	ReasonPaymentBelowInterest = "payment_below_interest"
+	ReasonTermOutOfRange       = "term_out_of_range"
+	ReasonPaymentTooLow        = "payment_too_low"
+)
@@ -0,0 +1,109 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Money is an amount in kopecks. Sums of Money values are exact, products
+// with rates are computed as decimals and rounded with an explicit Mode.
+type Money int64
+
+const (
+	Kopeck Money = 1
+	Ruble  Money = 100
+)
+
+// Mode is a rounding rule for the half-way cases.
+type Mode string
+
+const (
+	// HalfUp rounds half away from zero, the usual commercial rounding.
+	HalfUp Mode = "half_up"
+	// HalfEven rounds half to the nearest even unit (banker's rounding).
+	HalfEven Mode = "half_even"
+)
+
+// FromFloat converts rubles to Money rounding half up to kopecks.
+func FromFloat(rubles float64) Money {
+	return Money(math.Round(rubles * 100))
+}
+
+// FromFloatRound converts rubles to Money rounded to a multiple of unit.
+func FromFloatRound(rubles float64, unit Money, mode Mode) Money {
+	r := new(big.Rat).SetFloat64(rubles * 100)
+	if r == nil {
+		return 0
+	}
+	r.Quo(r, big.NewRat(int64(unit), 1))
+	return Money(roundRat(r, mode)) * unit
+}
+
+// Float returns the amount in rubles.
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// Round rounds the amount to a multiple of unit.
+func (m Money) Round(unit Money, mode Mode) Money {
+	return Money(roundRat(big.NewRat(int64(m), int64(unit)), mode)) * unit
+}
+
+// Percent returns m * rate% * num / den rounded to kopecks, e.g. the monthly
+// interest at an annual rate is balance.Percent(rate, 1, 12, mode).
+func (m Money) Percent(rate float64, num, den int64, mode Mode) Money {
+	r := decimal(rate)
+	r.Mul(r, big.NewRat(int64(m)*num, 100*den))
+	return Money(roundRat(r, mode))
+}
+
+// Div returns m / n rounded to kopecks.
+func (m Money) Div(n int64, mode Mode) Money {
+	return Money(roundRat(big.NewRat(int64(m), n), mode))
+}
+
+// Min returns the smaller of two amounts.
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// decimal converts v to its exact shortest decimal representation, so 8.1
+// is 81/10 rather than the nearest binary fraction.
+func decimal(v float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'f', -1, 64))
+	return r
+}
+
+// roundRat rounds r to an integer according to mode.
+func roundRat(r *big.Rat, mode Mode) int64 {
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compare the doubled remainder with the denominator
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	cmp := twice.Cmp(den)
+
+	if cmp > 0 || cmp == 0 && (mode != HalfEven || quo.Bit(0) == 1) {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return quo.Int64()
+}
@@ -0,0 +1,56 @@
+package money
+
+import "testing"
+
+func TestMoney_Round(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Money
+		unit  Money
+		mode  Mode
+		want  Money
+	}{
+		{"half up to rubles", 12350, Ruble, HalfUp, 12400},
+		{"half even to rubles rounds down to even", 12250, Ruble, HalfEven, 12200},
+		{"half even to rubles rounds up to even", 12350, Ruble, HalfEven, 12400},
+		{"below half", 12349, Ruble, HalfUp, 12300},
+		{"negative half up", -12350, Ruble, HalfUp, -12400},
+		{"kopecks unchanged", 12345, Kopeck, HalfUp, 12345},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.Round(tt.unit, tt.mode); got != tt.want {
+				t.Errorf("Round() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Percent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   Money
+		rate    float64
+		num     int64
+		den     int64
+		mode    Mode
+		want    Money
+		wantStr string
+	}{
+		{"monthly interest", FromFloat(4_000_000), 8, 1, 12, HalfUp, 2666667, "26666.67"},
+		{"decimal rate is exact", FromFloat(1000), 8.1, 1, 1, HalfUp, 8100, "81.00"},
+		{"half even on exact half", 50, 1, 1, 1, HalfEven, 0, "0.00"},
+		{"half up on exact half", 50, 1, 1, 1, HalfUp, 1, "0.01"},
+		{"actual days", FromFloat(1_000_000), 10, 31, 365, HalfUp, 849315, "8493.15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.value.Percent(tt.rate, tt.num, tt.den, tt.mode)
+			if got != tt.want || got.String() != tt.wantStr {
+				t.Errorf("Percent() = %s, want %s", got, tt.wantStr)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package money
+
+import "fmt"
+
+// Rounding is the policy for rounding regular payments. Unit is "kopeck"
+// or "ruble", the remainder is always settled by the last payment.
+type Rounding struct {
+	Unit string `mapstructure:"unit"`
+	Mode Mode   `mapstructure:"mode"`
+}
+
+// DefaultRounding rounds payments half up to whole rubles.
+func DefaultRounding() Rounding {
+	return Rounding{Unit: "ruble", Mode: HalfUp}
+}
+
+// Validate checks that the unit and mode are supported.
+func (r Rounding) Validate() error {
+	switch r.Unit {
+	case "kopeck", "ruble":
+	default:
+		return fmt.Errorf("unknown rounding unit %q", r.Unit)
+	}
+
+	switch r.Mode {
+	case HalfUp, HalfEven:
+	default:
+		return fmt.Errorf("unknown rounding mode %q", r.Mode)
+	}
+
+	return nil
+}
+
+// Payment rounds a regular payment given in rubles to the policy unit.
+func (r Rounding) Payment(rubles float64) Money {
+	unit := Kopeck
+	if r.Unit == "ruble" {
+		unit = Ruble
+	}
+	return FromFloatRound(rubles, unit, r.Mode)
+}